@@ -0,0 +1,214 @@
+package battle
+
+import (
+	"math/rand/v2"
+	"slices"
+)
+
+// DefaultLevel is used for combatants that do not specify one.
+const DefaultLevel = 50
+
+// maxTurns ends stalemates, e.g. two Pokémon that are immune to each other.
+const maxTurns = 100
+
+// Struggle is used by a combatant that has no damaging moves.
+var Struggle = Move{Name: "struggle", DamageClass: "physical", Power: 50}
+
+type Move struct {
+	Name        string
+	Type        string
+	DamageClass string
+	Power       int
+	// Accuracy is a percentage; zero means the move never misses.
+	Accuracy int
+}
+
+// Stats holds base stats as reported by the API, before level scaling.
+type Stats struct {
+	HP             int
+	Attack         int
+	Defense        int
+	SpecialAttack  int
+	SpecialDefense int
+	Speed          int
+}
+
+type Combatant struct {
+	Name  string
+	Level int
+	Types []string
+	Stats Stats
+	Moves []Move
+}
+
+type Turn struct {
+	Number        int     `json:"number"`
+	Attacker      string  `json:"attacker"`
+	Defender      string  `json:"defender"`
+	Move          string  `json:"move"`
+	Missed        bool    `json:"missed"`
+	Damage        int     `json:"damage"`
+	Effectiveness float64 `json:"effectiveness"`
+	DefenderHP    int     `json:"defender_hp"`
+}
+
+// Result records every turn of a battle. Winner is empty if it ended in a draw.
+type Result struct {
+	Winner string `json:"winner"`
+	Loser  string `json:"loser"`
+	Turns  []Turn `json:"turns"`
+}
+
+// Random is the subset of *rand.Rand the engine needs.
+type Random interface {
+	IntN(n int) int
+}
+
+type globalRandom struct{}
+
+func (globalRandom) IntN(n int) int {
+	return rand.IntN(n)
+}
+
+type Engine struct {
+	chart  TypeChart
+	random Random
+}
+
+// NewEngine returns an Engine. A nil chart or random falls back to StaticChart
+// and the global math/rand source.
+func NewEngine(chart TypeChart, random Random) *Engine {
+	if chart == nil {
+		chart = StaticChart{}
+	}
+	if random == nil {
+		random = globalRandom{}
+	}
+	return &Engine{chart: chart, random: random}
+}
+
+type fighter struct {
+	Combatant
+	hp int
+}
+
+func newFighter(combatant Combatant) *fighter {
+	if combatant.Level <= 0 {
+		combatant.Level = DefaultLevel
+	}
+	if len(combatant.Moves) == 0 {
+		combatant.Moves = []Move{Struggle}
+	}
+	return &fighter{Combatant: combatant, hp: maxHP(combatant.Stats.HP, combatant.Level)}
+}
+
+func maxHP(base, level int) int {
+	return 2*base*level/100 + level + 10
+}
+
+func scaledStat(base, level int) int {
+	return 2*base*level/100 + 5
+}
+
+// Run fights first against second until one faints or maxTurns is reached.
+// Each turn the faster Pokémon attacks first, using the move it expects to
+// deal the most damage.
+func (engine *Engine) Run(first, second Combatant) Result {
+	a, b := newFighter(first), newFighter(second)
+	result := Result{Turns: []Turn{}}
+	for number := 1; number <= maxTurns; number++ {
+		order := []*fighter{a, b}
+		if engine.goesSecond(a, b) {
+			order = []*fighter{b, a}
+		}
+		for i, attacker := range order {
+			defender := order[1-i]
+			turn := engine.attack(attacker, defender)
+			turn.Number = number
+			result.Turns = append(result.Turns, turn)
+			if defender.hp == 0 {
+				result.Winner = attacker.Name
+				result.Loser = defender.Name
+				return result
+			}
+		}
+	}
+	return result
+}
+
+func (engine *Engine) goesSecond(a, b *fighter) bool {
+	speedA := scaledStat(a.Stats.Speed, a.Level)
+	speedB := scaledStat(b.Stats.Speed, b.Level)
+	if speedA == speedB {
+		return engine.random.IntN(2) == 1
+	}
+	return speedB > speedA
+}
+
+func (engine *Engine) attack(attacker, defender *fighter) Turn {
+	move := engine.chooseMove(attacker, defender)
+	turn := Turn{
+		Attacker:      attacker.Name,
+		Defender:      defender.Name,
+		Move:          move.Name,
+		Effectiveness: engine.effectiveness(move, defender),
+	}
+	if move.Accuracy > 0 && engine.random.IntN(100) >= move.Accuracy {
+		turn.Missed = true
+		turn.DefenderHP = defender.hp
+		return turn
+	}
+	// The random factor spans 85% to 100% of the computed damage.
+	roll := 85 + engine.random.IntN(16)
+	turn.Damage = int(engine.expectedDamage(move, attacker, defender) * float64(roll) / 100)
+	if turn.Damage < 1 && turn.Effectiveness > 0 {
+		turn.Damage = 1
+	}
+	defender.hp = max(defender.hp-turn.Damage, 0)
+	turn.DefenderHP = defender.hp
+	return turn
+}
+
+func (engine *Engine) chooseMove(attacker, defender *fighter) Move {
+	return slices.MaxFunc(attacker.Moves, func(x, y Move) int {
+		scoreX := engine.expectedDamage(x, attacker, defender) * hitChance(x)
+		scoreY := engine.expectedDamage(y, attacker, defender) * hitChance(y)
+		switch {
+		case scoreX < scoreY:
+			return -1
+		case scoreX > scoreY:
+			return 1
+		}
+		return 0
+	})
+}
+
+func hitChance(move Move) float64 {
+	if move.Accuracy <= 0 {
+		return 1
+	}
+	return float64(move.Accuracy) / 100
+}
+
+func (engine *Engine) effectiveness(move Move, defender *fighter) float64 {
+	if move.Type == "" {
+		return 1
+	}
+	return engine.chart.Effectiveness(move.Type, defender.Types)
+}
+
+// expectedDamage is the main-series damage formula without the random factor
+// or critical hits.
+func (engine *Engine) expectedDamage(move Move, attacker, defender *fighter) float64 {
+	attack := scaledStat(attacker.Stats.Attack, attacker.Level)
+	defense := scaledStat(defender.Stats.Defense, defender.Level)
+	if move.DamageClass == "special" {
+		attack = scaledStat(attacker.Stats.SpecialAttack, attacker.Level)
+		defense = scaledStat(defender.Stats.SpecialDefense, defender.Level)
+	}
+	base := float64((2*attacker.Level/5+2)*move.Power*attack/defense)/50 + 2
+	if slices.Contains(attacker.Types, move.Type) {
+		base *= 1.5
+	}
+	return base * engine.effectiveness(move, defender)
+}
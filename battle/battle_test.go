@@ -0,0 +1,84 @@
+package battle
+
+import (
+	"testing"
+)
+
+// fixedRandom always returns the largest value allowed, so moves always miss
+// unless they have perfect accuracy and damage rolls are maximal.
+type fixedRandom struct{}
+
+func (fixedRandom) IntN(n int) int {
+	return n - 1
+}
+
+func TestStaticChartEffectiveness(t *testing.T) {
+	cases := []struct {
+		attacking string
+		defending []string
+		expected  float64
+	}{
+		{attacking: "water", defending: []string{"fire"}, expected: 2},
+		{attacking: "electric", defending: []string{"water", "flying"}, expected: 4},
+		{attacking: "grass", defending: []string{"fire", "flying"}, expected: 0.25},
+		{attacking: "ground", defending: []string{"electric", "flying"}, expected: 0},
+		{attacking: "normal", defending: []string{"water"}, expected: 1},
+	}
+	for _, c := range cases {
+		actual := StaticChart{}.Effectiveness(c.attacking, c.defending)
+		if actual != c.expected {
+			t.Errorf("Expected %s vs %v to be %v, got %v", c.attacking, c.defending, c.expected, actual)
+		}
+	}
+}
+
+func TestRunTypeAdvantageWins(t *testing.T) {
+	stats := Stats{HP: 50, Attack: 50, Defense: 50, SpecialAttack: 50, SpecialDefense: 50, Speed: 50}
+	squirtle := Combatant{
+		Name:  "squirtle",
+		Types: []string{"water"},
+		Stats: stats,
+		Moves: []Move{{Name: "water-gun", Type: "water", DamageClass: "special", Power: 40}},
+	}
+	charmander := Combatant{
+		Name:  "charmander",
+		Types: []string{"fire"},
+		Stats: stats,
+		Moves: []Move{{Name: "ember", Type: "fire", DamageClass: "special", Power: 40}},
+	}
+	result := NewEngine(nil, fixedRandom{}).Run(charmander, squirtle)
+	if result.Winner != "squirtle" || result.Loser != "charmander" {
+		t.Fatalf("Expected squirtle to win, got %+v", result)
+	}
+	last := result.Turns[len(result.Turns)-1]
+	if last.DefenderHP != 0 || last.Effectiveness != 2 {
+		t.Errorf("Expected a super effective finishing blow, got %+v", last)
+	}
+}
+
+func TestRunChoosesBestMove(t *testing.T) {
+	stats := Stats{HP: 50, Attack: 50, Defense: 50, SpecialAttack: 50, SpecialDefense: 50, Speed: 90}
+	pikachu := Combatant{
+		Name:  "pikachu",
+		Types: []string{"electric"},
+		Stats: stats,
+		Moves: []Move{
+			{Name: "quick-attack", Type: "normal", DamageClass: "physical", Power: 40},
+			{Name: "thunderbolt", Type: "electric", DamageClass: "special", Power: 90},
+		},
+	}
+	geodude := Combatant{Name: "geodude", Types: []string{"rock", "ground"}, Stats: Stats{HP: 40, Speed: 20}}
+	result := NewEngine(nil, fixedRandom{}).Run(pikachu, geodude)
+	if result.Turns[0].Move != "quick-attack" {
+		t.Errorf("Expected quick-attack against a ground type, got %s", result.Turns[0].Move)
+	}
+}
+
+func TestRunDrawsWhenNeitherCanHurtTheOther(t *testing.T) {
+	gastly := Combatant{Name: "gastly", Types: []string{"ghost"}, Moves: []Move{{Name: "lick", Type: "ghost", Power: 30}}}
+	snorlax := Combatant{Name: "snorlax", Types: []string{"normal"}, Moves: []Move{{Name: "tackle", Type: "normal", Power: 40}}}
+	result := NewEngine(nil, fixedRandom{}).Run(gastly, snorlax)
+	if result.Winner != "" {
+		t.Errorf("Expected a draw, got winner %s", result.Winner)
+	}
+}
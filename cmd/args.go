@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// argSpec describes what a command accepts: required positionals, then
+// optional ones, plus --flags that may appear anywhere.
+type argSpec struct {
+	required []string
+	optional []string
+	flags    []flagSpec
+}
+
+// flagSpec is a --name flag. An empty value placeholder makes it a boolean flag.
+type flagSpec struct {
+	name  string
+	value string
+}
+
+type arguments struct {
+	positional []string
+	flags      map[string]string
+}
+
+// usageError is returned when a command line does not match its argSpec.
+type usageError struct {
+	message string
+	usage   string
+}
+
+func (e *usageError) Error() string {
+	return e.message + "\n\tusage: " + e.usage
+}
+
+// get returns the i-th positional argument, or "" when it was not given.
+func (args arguments) get(i int) string {
+	if i >= len(args.positional) {
+		return ""
+	}
+	return args.positional[i]
+}
+
+func (args arguments) flag(name string) (value string, set bool) {
+	value, set = args.flags[name]
+	return value, set
+}
+
+func (spec argSpec) usage(command string) string {
+	parts := []string{command}
+	for _, name := range spec.required {
+		parts = append(parts, "<"+name+">")
+	}
+	for _, name := range spec.optional {
+		parts = append(parts, "["+name+"]")
+	}
+	for _, flag := range spec.flags {
+		if flag.value == "" {
+			parts = append(parts, "[--"+flag.name+"]")
+		} else {
+			parts = append(parts, "[--"+flag.name+" <"+flag.value+">]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (spec argSpec) lookupFlag(name string) (flag flagSpec, exists bool) {
+	for _, flag := range spec.flags {
+		if flag.name == name {
+			return flag, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+// parse matches tokens, which exclude the command name, against spec.
+// A literal "--" ends flag parsing.
+func (spec argSpec) parse(command string, tokens []token) (args arguments, err error) {
+	args = arguments{positional: []string{}, flags: map[string]string{}}
+	usageErr := func(format string, a ...any) error {
+		return &usageError{message: fmt.Sprintf(format, a...), usage: spec.usage(command)}
+	}
+	flagsDone := false
+	for i := 0; i < len(tokens); i++ {
+		word := tokens[i]
+		if flagsDone || !strings.HasPrefix(word.text, "--") || word.quoted {
+			args.positional = append(args.positional, word.text)
+			continue
+		}
+		if word.text == "--" {
+			flagsDone = true
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(word.text, "--"), "=")
+		flag, exists := spec.lookupFlag(name)
+		if !exists {
+			return args, usageErr("unknown flag --%s", name)
+		}
+		if flag.value == "" {
+			if hasValue {
+				return args, usageErr("flag --%s does not take a value", name)
+			}
+			args.flags[name] = ""
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(tokens) {
+				return args, usageErr("flag --%s needs a value", name)
+			}
+			i++
+			value = tokens[i].text
+		}
+		args.flags[name] = value
+	}
+	if len(args.positional) < len(spec.required) {
+		return args, usageErr("missing %s", spec.required[len(args.positional)])
+	}
+	if len(args.positional) > len(spec.required)+len(spec.optional) {
+		return args, usageErr("too many arguments")
+	}
+	return args, nil
+}
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+// tokenize splits a command line on whitespace. Single or double quotes group
+// words and keep their case; everything else is lowercased.
+func tokenize(line string) (tokens []token, err error) {
+	tokens = []token{}
+	var current strings.Builder
+	inToken, quoted := false, false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inToken, quoted = true, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				tokens = append(tokens, token{text: current.String(), quoted: quoted})
+				current.Reset()
+				inToken, quoted = false, false
+			}
+		default:
+			current.WriteString(strings.ToLower(string(r)))
+			inToken = true
+		}
+	}
+	if quote != 0 {
+		return tokens, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inToken {
+		tokens = append(tokens, token{text: current.String(), quoted: quoted})
+	}
+	return tokens, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{input: " Catch  PikacHu ", expected: []string{"catch", "pikachu"}},
+		{input: `rename 1 "Sir Sparks"`, expected: []string{"rename", "1", "Sir Sparks"}},
+		{input: `say 'it''s'`, expected: []string{"say", "its"}},
+		{input: "   ", expected: []string{}},
+		{input: " Charmander  BLAStOiSE\tRaIcHu ", expected: []string{"charmander", "blastoise", "raichu"}},
+	}
+	for _, c := range cases {
+		actual, err := tokenize(c.input)
+		if err != nil {
+			t.Fatalf("Unexpected error tokenizing %q: %v", c.input, err)
+		}
+		if len(actual) != len(c.expected) {
+			t.Fatalf("Expected %v tokens for %q, got %v", len(c.expected), c.input, actual)
+		}
+		for i := range actual {
+			if actual[i].text != c.expected[i] {
+				t.Errorf("Expected token %q, got %q", c.expected[i], actual[i].text)
+			}
+		}
+	}
+
+	_, err := tokenize(`catch "pikachu`)
+	if err == nil {
+		t.Errorf("Expected an error for an unterminated quote")
+	}
+}
+
+func TestArgSpecParse(t *testing.T) {
+	spec := argSpec{
+		required: []string{"pokemon"},
+		optional: []string{"ball"},
+		flags:    []flagSpec{{name: "hp", value: "percent"}, {name: "verbose"}},
+	}
+	tokens, _ := tokenize("pikachu --hp 25 ultraball --verbose")
+	args, err := spec.parse("catch", tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args.get(0) != "pikachu" || args.get(1) != "ultraball" || args.get(2) != "" {
+		t.Errorf("Unexpected positionals %v", args.positional)
+	}
+	if hp, _ := args.flag("hp"); hp != "25" {
+		t.Errorf("Expected --hp 25, got %q", hp)
+	}
+	if _, set := args.flag("verbose"); !set {
+		t.Errorf("Expected --verbose to be set")
+	}
+
+	tokens, _ = tokenize("pikachu --hp=50")
+	args, err = spec.parse("catch", tokens)
+	if hp, _ := args.flag("hp"); err != nil || hp != "50" {
+		t.Errorf("Expected --hp=50 to parse, got %q, %v", hp, err)
+	}
+
+	failures := []string{"", "pikachu ultraball extra", "pikachu --hp", "pikachu --shiny", "pikachu --verbose=yes"}
+	for _, input := range failures {
+		tokens, _ := tokenize(input)
+		_, err := spec.parse("catch", tokens)
+		var usageErr *usageError
+		if !errors.As(err, &usageErr) {
+			t.Errorf("Expected a usage error for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestArgSpecUsage(t *testing.T) {
+	spec := argSpec{required: []string{"mine", "wild"}, optional: []string{"ball"}, flags: []flagSpec{{name: "hp", value: "percent"}}}
+	expected := "battle <mine> <wild> [ball] [--hp <percent>]"
+	if actual := spec.usage("battle"); actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
@@ -1,83 +1,1174 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	pokecache "github.com/anantashahane/pokedex/pokecache"
 	pokedex "github.com/anantashahane/pokedex/pokedex"
 )
 
-func commandExit(configuration *config) error {
+func commandExit(ctx context.Context, configuration *config, args arguments) error {
+	autosave(configuration)
 	fmt.Println("\tClosing the Pokedex... Goodbye!")
 	os.Exit(0)
 	return fmt.Errorf("\tError quiting")
 }
 
-func makeHelpCommand(commands map[string]cliCommand) func(configuration *config) error {
-	return func(configuration *config) error {
-		fmt.Println("\tWelcome to the Pokedex!")
-		fmt.Println("\tUsage:")
-		fmt.Println("")
-		for _, cmd := range commands {
-			fmt.Printf("\t\t%s: %s\n", cmd.name, cmd.description)
+func makeHelpCommand(commands map[string]cliCommand) func(ctx context.Context, configuration *config, args arguments) error {
+	return func(ctx context.Context, configuration *config, args arguments) error {
+		type commandHelp struct {
+			Command     string `json:"command"`
+			Usage       string `json:"usage"`
+			Description string `json:"description"`
 		}
-		return nil
+		names := []string{}
+		for name := range commands {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		help := []commandHelp{}
+		out := output{columns: []string{"command", "usage", "description"}, rows: [][]string{}}
+		for _, name := range names {
+			cmd := commands[name]
+			help = append(help, commandHelp{Command: name, Usage: cmd.args.usage(cmd.name), Description: cmd.description})
+			out.rows = append(out.rows, []string{name, cmd.args.usage(cmd.name), cmd.description})
+		}
+		out.value = help
+		out.text = func(w io.Writer) {
+			fmt.Fprintln(w, "\tWelcome to the Pokedex!")
+			fmt.Fprintln(w, "\tUsage:")
+			fmt.Fprintln(w, "")
+			for _, command := range help {
+				fmt.Fprintf(w, "\t\t%s: %s\n", command.Usage, command.Description)
+			}
+		}
+		return configuration.render(out)
 	}
 }
 
-func commandMap(configuration *config) error {
-	locations, previous, next := pokedex.GetLocations(configuration.next)
-	configuration.previous = previous
-	configuration.next = next
-	for _, location := range locations {
-		fmt.Println("\t", location)
-	}
-	return nil
+func commandMap(ctx context.Context, configuration *config, args arguments) error {
+	return showLocations(ctx, configuration, configuration.next)
+}
+
+func commandMapb(ctx context.Context, configuration *config, args arguments) error {
+	return showLocations(ctx, configuration, configuration.previous)
 }
 
-func commandMapb(configuration *config) error {
-	locations, previous, next := pokedex.GetLocations(configuration.previous)
-	configuration.previous = previous
-	configuration.next = next
-	for _, location := range locations {
-		fmt.Println("\t", location)
+func showLocations(ctx context.Context, configuration *config, url string) error {
+	page, err := configuration.client.GetLocations(ctx, url)
+	if err != nil {
+		return err
+	}
+	configuration.previous = page.Previous
+	configuration.next = page.Next
+	configuration.locations = page.Locations
+	err = configuration.resolveNames(ctx, pokedex.LocationAreaName, page.Locations...)
+	if err != nil {
+		return err
+	}
+	out := output{columns: []string{"location"}, rows: [][]string{}, value: page}
+	for _, location := range page.Locations {
+		out.rows = append(out.rows, []string{configuration.localize(pokedex.LocationAreaName, location)})
 	}
-	return nil
+	return configuration.render(out)
 }
 
-func exploreMap(configuration *config) error {
-	pokemons, available := pokedex.GetPokemons("https://pokeapi.co/api/v2/location-area/" + configuration.variable)
-	if !available {
-		return nil
+func exploreMap(ctx context.Context, configuration *config, args arguments) error {
+	area := args.get(0)
+	pokemons, err := configuration.client.GetPokemons(ctx, area)
+	if err != nil {
+		return err
+	}
+	configuration.area = area
+	configuration.explored = pokemons
+	configuration.prefetchPokemon(pokemons)
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, pokemons...)
+	if err != nil {
+		return err
+	}
+	err = configuration.resolveNames(ctx, pokedex.LocationAreaName, area)
+	if err != nil {
+		return err
+	}
+	out := output{
+		columns: []string{"pokemon"},
+		rows:    [][]string{},
+		value: struct {
+			Area    string   `json:"area"`
+			Pokemon []string `json:"pokemon"`
+		}{Area: area, Pokemon: pokemons},
 	}
 	for _, pokemon := range pokemons {
-		fmt.Println("\t", pokemon)
+		out.rows = append(out.rows, []string{configuration.localize(pokedex.PokemonName, pokemon)})
+	}
+	if len(pokemons) == 0 {
+		out.text = func(w io.Writer) {
+			fmt.Fprintln(w, "\tNo Pokémon live in "+configuration.localize(pokedex.LocationAreaName, area)+".")
+		}
+	}
+	return configuration.render(out)
+}
+
+func catchPokemon(ctx context.Context, configuration *config, args arguments) error {
+	if area, set := args.flag("area"); set {
+		configuration.area = area
+	}
+	if configuration.area == "" {
+		return fmt.Errorf("explore an area before trying to catch pokemon")
+	}
+	name := args.get(0)
+	options := pokedex.CatchOptions{Area: configuration.area, Ball: args.get(1), Status: args.get(2)}
+	if hp, set := args.flag("hp"); set {
+		percent, err := strconv.Atoi(strings.TrimSuffix(hp, "%"))
+		if err != nil || percent < 1 || percent > 100 {
+			return fmt.Errorf("--hp must be a percentage between 1 and 100")
+		}
+		options.HPPercent = percent
+	}
+	ball := options.Ball
+	if ball == "" {
+		ball = pokedex.DefaultBall
+	}
+	err := configuration.resolveNames(ctx, pokedex.PokemonName, name)
+	if err != nil {
+		return err
+	}
+	if configuration.output == formatTable {
+		fmt.Println("Throwing a " + ball + " at " + configuration.localize(pokedex.PokemonName, name) + "...")
+	}
+	result, err := configuration.client.CatchPokemon(ctx, name, options)
+	if err != nil {
+		return err
+	}
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, result.Pokemon)
+	if err != nil {
+		return err
 	}
-	return nil
+	return configuration.render(output{
+		columns: []string{"pokemon", "ball", "shakes", "caught", "level", "chance", "id", "nature", "shiny", "box"},
+		rows: [][]string{{
+			result.Pokemon,
+			result.Ball,
+			strconv.Itoa(result.Shakes),
+			strconv.FormatBool(result.Caught),
+			strconv.Itoa(result.Level),
+			strconv.FormatFloat(result.Chance, 'f', 4, 64),
+			strconv.Itoa(result.ID),
+			result.Nature,
+			strconv.FormatBool(result.Shiny),
+			strconv.Itoa(result.Box),
+		}},
+		value: result,
+		text: func(w io.Writer) {
+			pokemon := configuration.localize(pokedex.PokemonName, result.Pokemon)
+			for shake := 1; shake <= min(result.Shakes, 3); shake++ {
+				fmt.Fprintln(w, "\t...shake "+strconv.Itoa(shake))
+			}
+			if result.Caught {
+				if result.Shiny {
+					fmt.Fprintf(w, "A shiny %s!\n", pokemon)
+				}
+				fmt.Fprintf(w, "%s (level %d, %s nature) was caught as #%d!\n", pokemon, result.Level, result.Nature, result.ID)
+				if result.Box > 0 {
+					fmt.Fprintf(w, "Your party is full, so it was sent to box %d.\n", result.Box)
+				}
+				fmt.Fprintln(w, "You may now inspect it with the inspect command.")
+			} else {
+				fmt.Fprintln(w, pokemon+" escaped!")
+			}
+		},
+	})
+}
+
+// inspection is the JSON form of inspect: the individual's details and the
+// stat and type block of its Pokémon without the bulky sprite and move data.
+type inspection struct {
+	ID             int        `json:"id"`
+	Nickname       string     `json:"nickname"`
+	Name           string     `json:"name"`
+	Level          int        `json:"level"`
+	Experience     int        `json:"experience"`
+	Happiness      int        `json:"happiness"`
+	HeldItem       string     `json:"held_item"`
+	Nature         string     `json:"nature"`
+	Shiny          bool       `json:"shiny"`
+	CaughtAt       time.Time  `json:"caught_at"`
+	CaughtIn       string     `json:"caught_in"`
+	Height         int        `json:"height"`
+	Weight         int        `json:"weight"`
+	BaseExperience int        `json:"base_experience"`
+	Stats          []statLine `json:"stats"`
+	Types          []string   `json:"types"`
+}
+
+// statLine is one stat of a caught Pokémon. Effort is the effort value
+// defeating its species yields; EV is what it has earned itself.
+type statLine struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+	Effort   int    `json:"effort"`
+	IV       int    `json:"iv"`
+	EV       int    `json:"ev"`
+	Value    int    `json:"value"`
 }
 
-func catchPokemon(configuration *config) error {
-	fmt.Println("Throwing a Pokeball at " + configuration.variable + "...")
-	caught, err := pokedex.CatchPokemon(configuration.variable)
+func inspectPokemon(ctx context.Context, configuration *config, args arguments) error {
+	caught, err := pokedex.Inspect(args.get(0))
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
-	if caught {
-		fmt.Println(configuration.variable + " was caught!")
-		fmt.Println("You may now inspect it with the inspect command.")
-	} else {
-		fmt.Println(configuration.variable + " escaped!")
+	pokemon := caught.Pokemon
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, pokemon.Name)
+	if err != nil {
+		return err
+	}
+	details := inspection{
+		ID:             caught.ID,
+		Nickname:       caught.Nickname,
+		Name:           pokemon.Name,
+		Level:          caught.Level,
+		Experience:     caught.Experience,
+		Happiness:      caught.Happiness,
+		HeldItem:       caught.HeldItem,
+		Nature:         caught.Nature,
+		Shiny:          caught.Shiny,
+		CaughtAt:       caught.CaughtAt,
+		CaughtIn:       caught.CaughtIn,
+		Height:         pokemon.Height,
+		Weight:         pokemon.Weight,
+		BaseExperience: pokemon.BaseExperience,
+		Stats:          []statLine{},
+		Types:          []string{},
+	}
+	caughtAt := ""
+	if !caught.CaughtAt.IsZero() {
+		caughtAt = caught.CaughtAt.Format(time.DateTime)
+	}
+	out := output{
+		columns: []string{"field", "value"},
+		rows: [][]string{
+			{"id", strconv.Itoa(caught.ID)},
+			{"nickname", caught.Nickname},
+			{"name", configuration.localize(pokedex.PokemonName, pokemon.Name)},
+			{"level", strconv.Itoa(caught.Level)},
+			{"experience", strconv.Itoa(caught.Experience)},
+			{"happiness", strconv.Itoa(caught.Happiness)},
+			{"held_item", caught.HeldItem},
+			{"nature", caught.Nature},
+			{"shiny", strconv.FormatBool(caught.Shiny)},
+			{"caught_at", caughtAt},
+			{"caught_in", caught.CaughtIn},
+			{"height", strconv.Itoa(pokemon.Height)},
+			{"weight", strconv.Itoa(pokemon.Weight)},
+			{"base_experience", strconv.Itoa(pokemon.BaseExperience)},
+		},
 	}
-	return nil
+	calculated := caught.CalculatedStats()
+	for _, stat := range pokemon.Stats {
+		name := stat.Stat.Name
+		details.Stats = append(details.Stats, statLine{
+			Name:     name,
+			BaseStat: stat.BaseStat,
+			Effort:   stat.Effort,
+			IV:       caught.IVs.Get(name),
+			EV:       caught.EVs.Get(name),
+			Value:    calculated.Get(name),
+		})
+		out.rows = append(out.rows, []string{name, strconv.Itoa(calculated.Get(name))})
+	}
+	for _, poketype := range pokemon.Types {
+		details.Types = append(details.Types, poketype.Type.Name)
+	}
+	err = configuration.resolveNames(ctx, pokedex.TypeName, details.Types...)
+	if err != nil {
+		return err
+	}
+	localTypes := configuration.localizeAll(pokedex.TypeName, details.Types)
+	out.rows = append(out.rows, []string{"types", strings.Join(localTypes, "/")})
+	out.value = details
+	out.text = func(w io.Writer) {
+		fmt.Fprintf(w, "\tID: #%d\n", details.ID)
+		if details.Nickname != "" {
+			fmt.Fprintln(w, "\tNickname:", details.Nickname)
+		}
+		fmt.Fprintln(w, "\tName:", configuration.localize(pokedex.PokemonName, details.Name))
+		if details.Shiny {
+			fmt.Fprintln(w, "\tShiny!")
+		}
+		fmt.Fprintln(w, "\tLevel:", details.Level)
+		fmt.Fprintln(w, "\tExperience:", details.Experience)
+		fmt.Fprintln(w, "\tNature:", details.Nature)
+		fmt.Fprintln(w, "\tFriendship:", details.Happiness)
+		if details.HeldItem != "" {
+			fmt.Fprintln(w, "\tHolding:", details.HeldItem)
+		}
+		if caughtAt != "" {
+			fmt.Fprintln(w, "\tCaught:", caughtAt, details.CaughtIn)
+		}
+		fmt.Fprintln(w, "\tHeight:", details.Height)
+		fmt.Fprintln(w, "\tWeight:", details.Weight)
+		fmt.Fprintln(w, "\tStats:")
+		for _, stat := range details.Stats {
+			fmt.Fprintf(w, "\t\t-%s: %d (base %d, IV %d, EV %d)\n", stat.Name, stat.Value, stat.BaseStat, stat.IV, stat.EV)
+		}
+		fmt.Fprintln(w, "\tTypes:")
+		for _, poketype := range localTypes {
+			fmt.Fprintf(w, "\t\t-%s\n", poketype)
+		}
+	}
+	return configuration.render(out)
 }
 
-func inspectPokemon(configuration *config) error {
-	pokedex.Inspect(configuration.variable)
-	return nil
+// pokedexEntry is the JSON form of one line of the pokedex listing.
+type pokedexEntry struct {
+	ID       int    `json:"id"`
+	Nickname string `json:"nickname"`
+	Pokemon  string `json:"pokemon"`
+	Level    int    `json:"level"`
+	Shiny    bool   `json:"shiny"`
+	// Box is where the Pokémon is kept, with 0 meaning the party.
+	Box int `json:"box"`
+	// label is the Pokémon's name in the configured language.
+	label string
+}
+
+func newPokedexEntry(configuration *config, caught pokedex.CaughtPokemon) pokedexEntry {
+	return pokedexEntry{
+		ID:       caught.ID,
+		Nickname: caught.Nickname,
+		Pokemon:  caught.Pokemon.Name,
+		Level:    caught.Level,
+		Shiny:    caught.Shiny,
+		Box:      pokedex.BoxOf(caught.ID),
+		label:    configuration.localize(pokedex.PokemonName, caught.Pokemon.Name),
+	}
 }
 
-func viewPokedex(configuration *config) error {
-	pokedex.ViewPokedex()
-	return nil
+// newPokedexEntries resolves the names of caught together before making
+// their entries.
+func newPokedexEntries(ctx context.Context, configuration *config, caught []pokedex.CaughtPokemon) (entries []pokedexEntry, err error) {
+	names := []string{}
+	for _, pokemon := range caught {
+		names = append(names, pokemon.Pokemon.Name)
+	}
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, names...)
+	if err != nil {
+		return []pokedexEntry{}, err
+	}
+	entries = []pokedexEntry{}
+	for _, pokemon := range caught {
+		entries = append(entries, newPokedexEntry(configuration, pokemon))
+	}
+	return entries, nil
+}
+
+func (entry pokedexEntry) row() []string {
+	return []string{
+		strconv.Itoa(entry.ID),
+		entry.Nickname,
+		entry.label,
+		strconv.Itoa(entry.Level),
+		strconv.FormatBool(entry.Shiny),
+		boxName(entry.Box),
+	}
+}
+
+// String describes the entry as "#3 Sparky (pikachu), level 12".
+func (entry pokedexEntry) String() string {
+	name := entry.label
+	if entry.Nickname != "" {
+		name = entry.Nickname + " (" + entry.label + ")"
+	}
+	shiny := ""
+	if entry.Shiny {
+		shiny = " *shiny*"
+	}
+	return fmt.Sprintf("#%d %s, level %d%s", entry.ID, name, entry.Level, shiny)
+}
+
+var pokedexColumns = []string{"id", "nickname", "pokemon", "level", "shiny", "location"}
+
+func boxName(box int) string {
+	if box == 0 {
+		return "party"
+	}
+	return "box " + strconv.Itoa(box)
+}
+
+func viewPokedex(ctx context.Context, configuration *config, args arguments) error {
+	entries, err := newPokedexEntries(ctx, configuration, pokedex.ViewPokedex())
+	if err != nil {
+		return err
+	}
+	out := output{columns: pokedexColumns, rows: [][]string{}, value: entries}
+	for _, entry := range entries {
+		out.rows = append(out.rows, entry.row())
+	}
+	out.text = func(w io.Writer) {
+		fmt.Fprintln(w, "\tYour Pokedex:")
+		for _, entry := range entries {
+			fmt.Fprintf(w, "\t\t- %s (%s)\n", entry, boxName(entry.Box))
+		}
+	}
+	return configuration.render(out)
+}
+
+func viewParty(ctx context.Context, configuration *config, args arguments) error {
+	entries, err := newPokedexEntries(ctx, configuration, pokedex.Party())
+	if err != nil {
+		return err
+	}
+	out := output{columns: pokedexColumns, rows: [][]string{}, value: entries}
+	for _, entry := range entries {
+		out.rows = append(out.rows, entry.row())
+	}
+	out.text = func(w io.Writer) {
+		fmt.Fprintf(w, "\tYour Party (%d/%d):\n", len(entries), pokedex.PartySize)
+		for i, entry := range entries {
+			fmt.Fprintf(w, "\t\t%d. %s\n", i+1, entry)
+		}
+	}
+	return configuration.render(out)
+}
+
+func depositPokemon(ctx context.Context, configuration *config, args arguments) error {
+	box := 0
+	if number := args.get(1); number != "" {
+		var err error
+		box, err = strconv.Atoi(number)
+		if err != nil || box < 1 {
+			return fmt.Errorf("box must be a number between 1 and %d", pokedex.BoxCount)
+		}
+	}
+	deposited, box, err := pokedex.Deposit(args.get(0), box)
+	if err != nil {
+		return err
+	}
+	return renderStorage(ctx, configuration, []pokedex.CaughtPokemon{deposited}, func(w io.Writer) {
+		fmt.Fprintf(w, "\t%s is in box %d.\n", deposited.Name(), box)
+	})
+}
+
+func withdrawPokemon(ctx context.Context, configuration *config, args arguments) error {
+	withdrawn, err := pokedex.Withdraw(args.get(0))
+	if err != nil {
+		return err
+	}
+	return renderStorage(ctx, configuration, []pokedex.CaughtPokemon{withdrawn}, func(w io.Writer) {
+		fmt.Fprintf(w, "\t%s joined your party.\n", withdrawn.Name())
+	})
+}
+
+func swapPokemon(ctx context.Context, configuration *config, args arguments) error {
+	a, b, err := pokedex.Swap(args.get(0), args.get(1))
+	if err != nil {
+		return err
+	}
+	return renderStorage(ctx, configuration, []pokedex.CaughtPokemon{a, b}, func(w io.Writer) {
+		fmt.Fprintf(w, "\t%s and %s swapped places.\n", a.Name(), b.Name())
+	})
+}
+
+func releasePokemon(ctx context.Context, configuration *config, args arguments) error {
+	released, err := pokedex.Release(args.get(0))
+	if err != nil {
+		return err
+	}
+	// A released Pokémon is no longer kept anywhere, so it has no location.
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, released.Pokemon.Name)
+	if err != nil {
+		return err
+	}
+	entry := newPokedexEntry(configuration, released)
+	return configuration.render(output{
+		columns: pokedexColumns[:len(pokedexColumns)-1],
+		rows:    [][]string{entry.row()[:len(pokedexColumns)-1]},
+		value: struct {
+			ID       int    `json:"id"`
+			Nickname string `json:"nickname"`
+			Pokemon  string `json:"pokemon"`
+			Level    int    `json:"level"`
+			Shiny    bool   `json:"shiny"`
+		}{ID: entry.ID, Nickname: entry.Nickname, Pokemon: entry.Pokemon, Level: entry.Level, Shiny: entry.Shiny},
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "\t%s was released. Bye, %s!\n", entry, released.Name())
+		},
+	})
+}
+
+// renderStorage shows where the Pokémon a storage command moved are now.
+func renderStorage(ctx context.Context, configuration *config, moved []pokedex.CaughtPokemon, text func(w io.Writer)) error {
+	entries, err := newPokedexEntries(ctx, configuration, moved)
+	if err != nil {
+		return err
+	}
+	out := output{columns: pokedexColumns, rows: [][]string{}, text: text}
+	for _, entry := range entries {
+		out.rows = append(out.rows, entry.row())
+	}
+	out.value = entries
+	return configuration.render(out)
+}
+
+func nicknamePokemon(ctx context.Context, configuration *config, args arguments) error {
+	reference := args.get(0)
+	caught, err := pokedex.SetNickname(reference, args.get(1))
+	if err != nil {
+		return err
+	}
+	return configuration.render(output{
+		columns: []string{"id", "pokemon", "nickname"},
+		rows:    [][]string{{strconv.Itoa(caught.ID), caught.Pokemon.Name, caught.Nickname}},
+		value: struct {
+			ID       int    `json:"id"`
+			Pokemon  string `json:"pokemon"`
+			Nickname string `json:"nickname"`
+		}{ID: caught.ID, Pokemon: caught.Pokemon.Name, Nickname: caught.Nickname},
+		text: func(w io.Writer) {
+			if caught.Nickname == "" {
+				fmt.Fprintf(w, "\tRemoved the nickname of %s.\n", reference)
+				return
+			}
+			fmt.Fprintf(w, "\t%s is now called %s.\n", reference, caught.Nickname)
+		},
+	})
+}
+
+func movesPokemon(ctx context.Context, configuration *config, args arguments) error {
+	options := pokedex.LearnsetOptions{}
+	options.Game, _ = args.flag("game")
+	options.Method, _ = args.flag("method")
+	_, options.Details = args.flag("details")
+	moves, err := configuration.client.GetLearnset(ctx, args.get(0), options)
+	if err != nil {
+		return err
+	}
+	names, types := []string{}, []string{}
+	for _, move := range moves {
+		names = append(names, move.Name)
+		types = append(types, move.Type)
+	}
+	err = configuration.resolveNames(ctx, pokedex.MoveName, names...)
+	if err != nil {
+		return err
+	}
+	err = configuration.resolveNames(ctx, pokedex.TypeName, types...)
+	if err != nil {
+		return err
+	}
+	out := output{columns: []string{"name", "method", "level", "game"}, rows: [][]string{}, value: moves}
+	if options.Details {
+		out.columns = append(out.columns, "type", "damage_class", "power", "accuracy", "pp")
+	}
+	for _, move := range moves {
+		row := []string{configuration.localize(pokedex.MoveName, move.Name), move.Method, strconv.Itoa(move.Level), move.Game}
+		if options.Details {
+			row = append(row, configuration.localize(pokedex.TypeName, move.Type), move.DamageClass, strconv.Itoa(move.Power), strconv.Itoa(move.Accuracy), strconv.Itoa(move.PP))
+		}
+		out.rows = append(out.rows, row)
+	}
+	out.text = func(w io.Writer) {
+		if len(moves) == 0 {
+			fmt.Fprintln(w, "\tNo moves match.")
+			return
+		}
+		method := ""
+		for _, move := range moves {
+			if move.Method != method {
+				method = move.Method
+				fmt.Fprintf(w, "\t%s:\n", method)
+			}
+			line := "\t\t-"
+			if move.Method == "level-up" {
+				line += fmt.Sprintf(" lv %d", move.Level)
+			}
+			line += " " + configuration.localize(pokedex.MoveName, move.Name)
+			if options.Details {
+				line += fmt.Sprintf(" [%s, %s, power %d, accuracy %d, pp %d]", configuration.localize(pokedex.TypeName, move.Type), move.DamageClass, move.Power, move.Accuracy, move.PP)
+			}
+			if options.Game == "" {
+				line += " (" + move.Game + ")"
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+	return configuration.render(out)
+}
+
+func battlePokemon(ctx context.Context, configuration *config, args arguments) error {
+	mine, wild := args.get(0), args.get(1)
+	result, err := configuration.client.Battle(ctx, configuration.engine, mine, wild)
+	if err != nil {
+		return err
+	}
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, wild)
+	if err != nil {
+		return err
+	}
+	moves := []string{}
+	for _, turn := range result.Turns {
+		moves = append(moves, turn.Move)
+	}
+	err = configuration.resolveNames(ctx, pokedex.MoveName, moves...)
+	if err != nil {
+		return err
+	}
+	out := output{
+		columns: []string{"turn", "attacker", "move", "missed", "damage", "effectiveness", "defender", "defender_hp"},
+		rows:    [][]string{},
+		value:   result,
+	}
+	for _, turn := range result.Turns {
+		out.rows = append(out.rows, []string{
+			strconv.Itoa(turn.Number),
+			turn.Attacker,
+			turn.Move,
+			strconv.FormatBool(turn.Missed),
+			strconv.Itoa(turn.Damage),
+			strconv.FormatFloat(turn.Effectiveness, 'g', -1, 64),
+			turn.Defender,
+			strconv.Itoa(turn.DefenderHP),
+		})
+	}
+	out.text = func(w io.Writer) {
+		fmt.Fprintf(w, "\t%s vs %s!\n", mine, configuration.localize(pokedex.PokemonName, wild))
+		for _, turn := range result.Turns {
+			move := configuration.localize(pokedex.MoveName, turn.Move)
+			if turn.Missed {
+				fmt.Fprintf(w, "\tTurn %d: %s used %s, but it missed.\n", turn.Number, turn.Attacker, move)
+				continue
+			}
+			fmt.Fprintf(w, "\tTurn %d: %s used %s for %d damage%s (%s has %d HP left)\n",
+				turn.Number, turn.Attacker, move, turn.Damage, effectivenessNote(turn.Effectiveness), turn.Defender, turn.DefenderHP)
+		}
+		if result.Winner == "" {
+			fmt.Fprintln(w, "\tThe battle ended in a draw.")
+			return
+		}
+		fmt.Fprintf(w, "\t%s fainted. %s wins!\n", result.Loser, result.Winner)
+		if result.Experience > 0 {
+			fmt.Fprintf(w, "\t%s gained %d experience.\n", mine, result.Experience)
+		}
+		if result.LevelsUp > 0 {
+			fmt.Fprintf(w, "\t%s grew to level %d!\n", mine, result.Level)
+		}
+	}
+	return configuration.render(out)
+}
+
+func effectivenessNote(multiplier float64) string {
+	switch {
+	case multiplier == 0:
+		return ", it had no effect"
+	case multiplier > 1:
+		return ", it's super effective"
+	case multiplier < 1:
+		return ", it's not very effective"
+	}
+	return ""
+}
+
+// weaknessMultipliers lists the multipliers weakness reports, strongest first.
+var weaknessMultipliers = []float64{4, 2, 0.5, 0.25, 0}
+
+func weaknessPokemon(ctx context.Context, configuration *config, args arguments) error {
+	name := args.get(0)
+	types, err := configuration.client.PokemonTypes(ctx, name)
+	if err != nil {
+		return err
+	}
+	chart := configuration.client.TypeChart()
+	err = chart.Load(ctx, types...)
+	if err != nil {
+		return err
+	}
+	weaknesses := chart.Weaknesses(types)
+	byMultiplier := map[float64][]string{}
+	allTypes := slices.Clone(types)
+	for attacking, multiplier := range weaknesses {
+		byMultiplier[multiplier] = append(byMultiplier[multiplier], attacking)
+		allTypes = append(allTypes, attacking)
+	}
+	err = configuration.resolveNames(ctx, pokedex.TypeName, allTypes...)
+	if err != nil {
+		return err
+	}
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, name)
+	if err != nil {
+		return err
+	}
+	out := output{
+		columns: []string{"type", "multiplier"},
+		rows:    [][]string{},
+		value: struct {
+			Pokemon     string             `json:"pokemon"`
+			Types       []string           `json:"types"`
+			Multipliers map[string]float64 `json:"multipliers"`
+		}{Pokemon: name, Types: types, Multipliers: weaknesses},
+	}
+	for _, multiplier := range weaknessMultipliers {
+		slices.Sort(byMultiplier[multiplier])
+		for _, attacking := range byMultiplier[multiplier] {
+			out.rows = append(out.rows, []string{configuration.localize(pokedex.TypeName, attacking), strconv.FormatFloat(multiplier, 'g', -1, 64)})
+		}
+	}
+	out.text = func(w io.Writer) {
+		localTypes := configuration.localizeAll(pokedex.TypeName, types)
+		fmt.Fprintf(w, "\t%s (%s)\n", configuration.localize(pokedex.PokemonName, name), strings.Join(localTypes, "/"))
+		for _, multiplier := range weaknessMultipliers {
+			attackers := configuration.localizeAll(pokedex.TypeName, byMultiplier[multiplier])
+			if len(attackers) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "\t\t%vx: %s\n", multiplier, strings.Join(attackers, ", "))
+		}
+	}
+	return configuration.render(out)
+}
+
+// speciesSummary is the JSON form of species.
+type speciesSummary struct {
+	Name          string `json:"name"`
+	ID            int    `json:"id"`
+	Genus         string `json:"genus"`
+	Generation    string `json:"generation"`
+	Habitat       string `json:"habitat"`
+	CaptureRate   int    `json:"capture_rate"`
+	BaseHappiness int    `json:"base_happiness"`
+	GrowthRate    string `json:"growth_rate"`
+	Legendary     bool   `json:"legendary"`
+	Mythical      bool   `json:"mythical"`
+	EvolvesFrom   string `json:"evolves_from"`
+	Description   string `json:"description"`
+}
+
+func speciesPokemon(ctx context.Context, configuration *config, args arguments) error {
+	species, err := configuration.client.GetSpecies(ctx, args.get(0))
+	if err != nil {
+		return err
+	}
+	summary := speciesSummary{
+		Name:          species.Name,
+		ID:            species.ID,
+		Genus:         species.EnglishGenus(),
+		Generation:    species.Generation.Name,
+		CaptureRate:   species.CaptureRate,
+		BaseHappiness: species.BaseHappiness,
+		GrowthRate:    species.GrowthRate.Name,
+		Legendary:     species.IsLegendary,
+		Mythical:      species.IsMythical,
+		Description:   species.EnglishFlavorText(),
+	}
+	if species.Habitat != nil {
+		summary.Habitat = species.Habitat.Name
+	}
+	if species.EvolvesFromSpecies != nil {
+		summary.EvolvesFrom = species.EvolvesFromSpecies.Name
+	}
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, summary.Name)
+	if err != nil {
+		return err
+	}
+	return configuration.render(output{
+		columns: []string{"field", "value"},
+		rows: [][]string{
+			{"name", configuration.localize(pokedex.PokemonName, summary.Name)},
+			{"id", strconv.Itoa(summary.ID)},
+			{"genus", summary.Genus},
+			{"generation", summary.Generation},
+			{"habitat", summary.Habitat},
+			{"capture_rate", strconv.Itoa(summary.CaptureRate)},
+			{"base_happiness", strconv.Itoa(summary.BaseHappiness)},
+			{"growth_rate", summary.GrowthRate},
+			{"legendary", strconv.FormatBool(summary.Legendary)},
+			{"mythical", strconv.FormatBool(summary.Mythical)},
+			{"evolves_from", summary.EvolvesFrom},
+			{"description", summary.Description},
+		},
+		value: summary,
+	})
+}
+
+// abilitySummary is the JSON form of ability.
+type abilitySummary struct {
+	Name       string   `json:"name"`
+	Generation string   `json:"generation"`
+	Effect     string   `json:"effect"`
+	Pokemon    []string `json:"pokemon"`
+	Hidden     []string `json:"hidden"`
+}
+
+func abilityDetails(ctx context.Context, configuration *config, args arguments) error {
+	ability, err := configuration.client.FetchAbility(ctx, args.get(0))
+	if err != nil {
+		return err
+	}
+	summary := abilitySummary{
+		Name:       ability.Name,
+		Generation: ability.Generation.Name,
+		Effect:     ability.EnglishEffect(),
+		Pokemon:    []string{},
+		Hidden:     []string{},
+	}
+	for _, holder := range ability.Pokemon {
+		if holder.IsHidden {
+			summary.Hidden = append(summary.Hidden, holder.Pokemon.Name)
+		} else {
+			summary.Pokemon = append(summary.Pokemon, holder.Pokemon.Name)
+		}
+	}
+	return configuration.render(output{
+		columns: []string{"field", "value"},
+		rows: [][]string{
+			{"name", summary.Name},
+			{"generation", summary.Generation},
+			{"effect", summary.Effect},
+			{"pokemon", strings.Join(summary.Pokemon, ", ")},
+			{"hidden", strings.Join(summary.Hidden, ", ")},
+		},
+		value: summary,
+	})
+}
+
+// moveSummary is the JSON form of move.
+type moveSummary struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	DamageClass string   `json:"damage_class"`
+	Power       int      `json:"power"`
+	Accuracy    int      `json:"accuracy"`
+	PP          int      `json:"pp"`
+	Priority    int      `json:"priority"`
+	Target      string   `json:"target"`
+	Generation  string   `json:"generation"`
+	Effect      string   `json:"effect"`
+	LearnedBy   []string `json:"learned_by"`
+}
+
+func moveDetails(ctx context.Context, configuration *config, args arguments) error {
+	move, err := configuration.client.FetchMove(ctx, args.get(0))
+	if err != nil {
+		return err
+	}
+	summary := moveSummary{
+		Name:        move.Name,
+		Type:        move.Type.Name,
+		DamageClass: move.DamageClass.Name,
+		Power:       move.Power,
+		Accuracy:    move.Accuracy,
+		PP:          move.Pp,
+		Priority:    move.Priority,
+		Target:      move.Target.Name,
+		Generation:  move.Generation.Name,
+		Effect:      move.EnglishEffect(),
+		LearnedBy:   []string{},
+	}
+	for _, learner := range move.LearnedByPokemon {
+		summary.LearnedBy = append(summary.LearnedBy, learner.Name)
+	}
+	err = configuration.resolveNames(ctx, pokedex.MoveName, summary.Name)
+	if err != nil {
+		return err
+	}
+	err = configuration.resolveNames(ctx, pokedex.TypeName, summary.Type)
+	if err != nil {
+		return err
+	}
+	return configuration.render(output{
+		columns: []string{"field", "value"},
+		rows: [][]string{
+			{"name", configuration.localize(pokedex.MoveName, summary.Name)},
+			{"type", configuration.localize(pokedex.TypeName, summary.Type)},
+			{"damage_class", summary.DamageClass},
+			{"power", strconv.Itoa(summary.Power)},
+			{"accuracy", strconv.Itoa(summary.Accuracy)},
+			{"pp", strconv.Itoa(summary.PP)},
+			{"priority", strconv.Itoa(summary.Priority)},
+			{"target", summary.Target},
+			{"generation", summary.Generation},
+			{"effect", summary.Effect},
+			{"learned_by", strings.Join(summary.LearnedBy, ", ")},
+		},
+		value: summary,
+	})
+}
+
+func evolutionPokemon(ctx context.Context, configuration *config, args arguments) error {
+	root, err := configuration.client.GetEvolutionChain(ctx, args.get(0))
+	if err != nil {
+		return err
+	}
+	name := func(species string) string {
+		return configuration.localize(pokedex.PokemonName, species)
+	}
+	species := []string{}
+	var walk func(node pokedex.EvolutionNode)
+	walk = func(node pokedex.EvolutionNode) {
+		species = append(species, node.Species)
+		for _, next := range node.EvolvesTo {
+			walk(next)
+		}
+	}
+	walk(root)
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, species...)
+	if err != nil {
+		return err
+	}
+	out := output{columns: []string{"from", "to", "conditions"}, rows: [][]string{}, value: root}
+	var collect func(node pokedex.EvolutionNode)
+	collect = func(node pokedex.EvolutionNode) {
+		for _, next := range node.EvolvesTo {
+			out.rows = append(out.rows, []string{name(node.Species), name(next.Species), describeConditions(next.Conditions)})
+			collect(next)
+		}
+	}
+	collect(root)
+	out.text = func(w io.Writer) {
+		fmt.Fprintln(w, "\t"+name(root.Species))
+		printEvolutions(w, root, "\t", name)
+	}
+	return configuration.render(out)
+}
+
+// printEvolutions draws the stages after node as an indented tree, naming
+// each species with name.
+func printEvolutions(w io.Writer, node pokedex.EvolutionNode, indent string, name func(species string) string) {
+	for i, next := range node.EvolvesTo {
+		branch, continuation := "├─ ", "│  "
+		if i == len(node.EvolvesTo)-1 {
+			branch, continuation = "└─ ", "   "
+		}
+		fmt.Fprintf(w, "%s%s%s (%s)\n", indent, branch, name(next.Species), describeConditions(next.Conditions))
+		printEvolutions(w, next, indent+continuation, name)
+	}
+}
+
+// describeConditions joins alternative ways of evolving, e.g. for different games.
+func describeConditions(conditions []pokedex.EvolutionDetail) string {
+	descriptions := []string{}
+	for _, condition := range conditions {
+		description := condition.String()
+		if !slices.Contains(descriptions, description) {
+			descriptions = append(descriptions, description)
+		}
+	}
+	return strings.Join(descriptions, " or ")
+}
+
+func evolvePokemon(ctx context.Context, configuration *config, args arguments) error {
+	options := pokedex.EvolveOptions{}
+	options.Item, _ = args.flag("item")
+	_, options.Trade = args.flag("trade")
+	result, err := configuration.client.Evolve(ctx, args.get(0), options)
+	if err != nil {
+		return err
+	}
+	err = configuration.resolveNames(ctx, pokedex.PokemonName, result.To)
+	if err != nil {
+		return err
+	}
+	return configuration.render(output{
+		columns: []string{"from", "to", "condition", "level"},
+		rows:    [][]string{{result.From, result.To, result.Condition, strconv.Itoa(result.Level)}},
+		value:   result,
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "\tWhat? %s is evolving!\n", result.From)
+			fmt.Fprintf(w, "\tCongratulations! Your %s evolved into %s!\n", result.From, configuration.localize(pokedex.PokemonName, result.To))
+		},
+	})
+}
+
+func givePokemon(ctx context.Context, configuration *config, args arguments) error {
+	caught, err := pokedex.GiveItem(args.get(0), args.get(1))
+	if err != nil {
+		return err
+	}
+	return configuration.render(output{
+		columns: []string{"id", "pokemon", "held_item"},
+		rows:    [][]string{{strconv.Itoa(caught.ID), caught.Name(), caught.HeldItem}},
+		value: struct {
+			ID       int    `json:"id"`
+			Pokemon  string `json:"pokemon"`
+			HeldItem string `json:"held_item"`
+		}{ID: caught.ID, Pokemon: caught.Name(), HeldItem: caught.HeldItem},
+		text: func(w io.Writer) {
+			if caught.HeldItem == "" {
+				fmt.Fprintf(w, "\tTook the held item from %s.\n", caught.Name())
+				return
+			}
+			fmt.Fprintf(w, "\t%s is now holding %s.\n", caught.Name(), caught.HeldItem)
+		},
+	})
+}
+
+// setOption changes a session setting.
+func setOption(ctx context.Context, configuration *config, args arguments) error {
+	key, value := args.get(0), args.get(1)
+	switch key {
+	case "output":
+		format, err := parseOutputFormat(value)
+		if err != nil {
+			return err
+		}
+		configuration.output = format
+	case "lang":
+		language, err := parseLanguage(value)
+		if err != nil {
+			return err
+		}
+		configuration.language = language
+	default:
+		return fmt.Errorf("unknown setting %q, expected output or lang", key)
+	}
+	return configuration.render(output{
+		columns: []string{"setting", "value"},
+		rows:    [][]string{{key, value}},
+		value: struct {
+			Setting string `json:"setting"`
+			Value   string `json:"value"`
+		}{Setting: key, Value: value},
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "\t%s set to %s.\n", key, value)
+		},
+	})
+}
+
+// parseLanguage accepts the API's language codes in any case, and "slug" to
+// go back to showing API slugs.
+func parseLanguage(value string) (language string, err error) {
+	if value == "slug" {
+		return "", nil
+	}
+	for _, language := range pokedex.Languages {
+		if strings.EqualFold(language, value) {
+			return language, nil
+		}
+	}
+	return "", fmt.Errorf("unknown language %q, expected slug or one of %s", value, strings.Join(pokedex.Languages, ", "))
+}
+
+func cacheCommand(ctx context.Context, configuration *config, args arguments) error {
+	switch action := args.get(0); action {
+	case "stats":
+		return cacheStats(configuration)
+	default:
+		return fmt.Errorf("unknown cache action %q, expected stats", action)
+	}
+}
+
+func cacheStats(configuration *config) error {
+	responses, decoded := configuration.client.CacheStats(), configuration.client.DecodedCacheStats()
+	limit := func(value int) string {
+		if value == 0 {
+			return "unlimited"
+		}
+		return strconv.Itoa(value)
+	}
+	out := output{
+		columns: []string{"cache", "hits", "misses", "hit_rate", "evictions", "expirations", "entries", "max_entries", "bytes", "max_bytes"},
+		rows:    [][]string{},
+		value: struct {
+			Responses pokecache.Stats `json:"responses"`
+			Decoded   pokecache.Stats `json:"decoded"`
+		}{Responses: responses, Decoded: decoded},
+	}
+	// Decoded values are not measured in bytes.
+	caches := []struct {
+		name        string
+		stats       pokecache.Stats
+		tracksBytes bool
+	}{{"responses", responses, true}, {"decoded", decoded, false}}
+	for _, cache := range caches {
+		stats := cache.stats
+		bytes, maxBytes := "", ""
+		if cache.tracksBytes {
+			bytes, maxBytes = strconv.Itoa(stats.Bytes), limit(stats.MaxBytes)
+		}
+		out.rows = append(out.rows, []string{
+			cache.name,
+			strconv.Itoa(stats.Hits), strconv.Itoa(stats.Misses), fmt.Sprintf("%.1f", hitRate(stats)),
+			strconv.Itoa(stats.Evictions), strconv.Itoa(stats.Expirations),
+			strconv.Itoa(stats.Entries), limit(stats.MaxEntries),
+			bytes, maxBytes,
+		})
+	}
+	out.text = func(w io.Writer) {
+		for _, cache := range caches {
+			stats := cache.stats
+			fmt.Fprintf(w, "\t%s:\n", cache.name)
+			fmt.Fprintf(w, "\t\tHits: %d\n", stats.Hits)
+			fmt.Fprintf(w, "\t\tMisses: %d\n", stats.Misses)
+			fmt.Fprintf(w, "\t\tHit rate: %.1f%%\n", hitRate(stats))
+			fmt.Fprintf(w, "\t\tEvictions: %d\n", stats.Evictions)
+			fmt.Fprintf(w, "\t\tExpirations: %d\n", stats.Expirations)
+			fmt.Fprintf(w, "\t\tEntries: %d / %s\n", stats.Entries, limit(stats.MaxEntries))
+			if cache.tracksBytes {
+				fmt.Fprintf(w, "\t\tBytes: %d / %s\n", stats.Bytes, limit(stats.MaxBytes))
+			}
+		}
+	}
+	return configuration.render(out)
+}
+
+// hitRate is the percentage of lookups that stats counts as hits.
+func hitRate(stats pokecache.Stats) float64 {
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		return 100 * float64(stats.Hits) / float64(lookups)
+	}
+	return 0
+}
+
+func savePokedex(ctx context.Context, configuration *config, args arguments) error {
+	if _, force := args.flag("force"); configuration.loadErr != nil && !force {
+		return fmt.Errorf("not replacing the save file, which failed to load: %w; run `save --force` to replace it", configuration.loadErr)
+	}
+	err := pokedex.SavePokedex(configuration.store)
+	if err != nil {
+		return err
+	}
+	configuration.loadErr = nil
+	return configuration.render(storeOutput("saved"))
+}
+
+func loadPokedex(ctx context.Context, configuration *config, args arguments) error {
+	err := pokedex.LoadPokedex(configuration.store)
+	if err != nil {
+		return err
+	}
+	configuration.loadErr = nil
+	return configuration.render(storeOutput("loaded"))
+}
+
+// storeOutput reports a save or load along with how many Pokémon it covered.
+func storeOutput(action string) output {
+	count := len(pokedex.ViewPokedex())
+	return output{
+		columns: []string{"action", "pokemon"},
+		rows:    [][]string{{action, strconv.Itoa(count)}},
+		value: struct {
+			Action  string `json:"action"`
+			Pokemon int    `json:"pokemon"`
+		}{Action: action, Pokemon: count},
+		text: func(w io.Writer) {
+			fmt.Fprintf(w, "\tPokedex %s.\n", action)
+		},
+	}
+}
+
+func autosave(configuration *config) {
+	if configuration.loadErr != nil {
+		fmt.Fprintln(os.Stderr, "\tAutosave skipped, the save file failed to load. Run `save --force` to replace it.")
+		return
+	}
+	err := pokedex.SavePokedex(configuration.store)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "\tAutosave failed:", err)
+	}
 }
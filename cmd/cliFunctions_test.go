@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pokedex "github.com/anantashahane/pokedex/pokedex"
+)
+
+func TestFailedLoadIsNotOverwritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	saved := `{"version":99,"next_id":7,"pokemon":[]}`
+	err := os.WriteFile(path, []byte(saved), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configuration := config{output: formatJSON, store: pokedex.NewJSONFileStore(path)}
+	configuration.loadErr = pokedex.LoadPokedex(configuration.store)
+	if configuration.loadErr == nil {
+		t.Fatal("Expected a save file from a newer version to fail to load")
+	}
+
+	autosave(&configuration)
+	err = savePokedex(context.Background(), &configuration, arguments{})
+	if err == nil {
+		t.Errorf("Expected save to refuse to replace a save file that failed to load")
+	}
+	if data, _ := os.ReadFile(path); string(data) != saved {
+		t.Fatalf("Expected the save file to be left alone, got %s", data)
+	}
+
+	err = savePokedex(context.Background(), &configuration, arguments{flags: map[string]string{"force": ""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) == saved {
+		t.Errorf("Expected save --force to replace the save file")
+	}
+	if configuration.loadErr != nil {
+		t.Errorf("Expected save --force to allow autosaving again")
+	}
+}
@@ -1,29 +1,161 @@
 package main
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"time"
 
+	battle "github.com/anantashahane/pokedex/battle"
+	pokecache "github.com/anantashahane/pokedex/pokecache"
 	pokedex "github.com/anantashahane/pokedex/pokedex"
 )
 
 type cliCommand struct {
 	name        string
 	description string
-	callback    func(configuration *config) error
+	args        argSpec
+	// complete holds a completion source per positional argument.
+	complete []completionSource
+	callback func(ctx context.Context, configuration *config, args arguments) error
+	// changesCollection marks commands that change the caught Pokémon, which
+	// one-shot mode saves after.
+	changesCollection bool
 }
 
 type config struct {
 	previous string
-	variable string
-	next     string
+	area     string
+	// locations and explored hold the last map page and explore result, for completion.
+	locations []string
+	explored  []string
+	next      string
+	store     pokedex.SaveStore
+	client    *pokedex.Client
+	engine    *battle.Engine
+	output    outputFormat
+	// language names locations, Pokémon, moves and types in table and CSV
+	// output; empty shows API slugs.
+	language string
+	// prefetch has explore fetch the area's Pokémon in the background, which
+	// only pays off in the REPL. stopPrefetch cancels the one in flight.
+	prefetch     bool
+	stopPrefetch context.CancelFunc
+	// names holds the localized names resolveNames has looked up, so
+	// rendering never fetches.
+	names map[nameKey]string
+	// loadErr is why the save file failed to load at startup. While it is
+	// set, nothing overwrites the save file unless saved with --force.
+	loadErr error
+}
+
+// nameKey identifies a localized name in names.
+type nameKey struct {
+	kind     pokedex.NameKind
+	language string
+	slug     string
+}
+
+// prefetchPokemon starts fetching names in the background, cancelling any
+// earlier prefetch so only the latest explored area is fetched.
+func (configuration *config) prefetchPokemon(names []string) {
+	if !configuration.prefetch {
+		return
+	}
+	configuration.cancelPrefetch()
+	ctx, cancel := context.WithCancel(context.Background())
+	configuration.stopPrefetch = cancel
+	go configuration.client.PrefetchPokemon(ctx, names, pokedex.DefaultPrefetchWorkers)
+}
+
+func (configuration *config) cancelPrefetch() {
+	if configuration.stopPrefetch != nil {
+		configuration.stopPrefetch()
+		configuration.stopPrefetch = nil
+	}
+}
+
+// resolveNames looks up the names of slugs in the configured language, a
+// bounded number at a time, for localize to use while rendering. JSON output
+// always keeps slugs, so nothing is fetched for it.
+func (configuration *config) resolveNames(ctx context.Context, kind pokedex.NameKind, slugs ...string) error {
+	if configuration.output == formatJSON || configuration.language == "" {
+		return nil
+	}
+	if configuration.names == nil {
+		configuration.names = map[nameKey]string{}
+	}
+	missing := []string{}
+	for _, slug := range slugs {
+		key := nameKey{kind, configuration.language, slug}
+		if _, resolved := configuration.names[key]; !resolved && !slices.Contains(missing, slug) {
+			missing = append(missing, slug)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	names, err := configuration.client.LocalizedNames(ctx, kind, missing, configuration.language, pokedex.DefaultLocalizeWorkers)
+	if err != nil {
+		return err
+	}
+	for slug, name := range names {
+		configuration.names[nameKey{kind, configuration.language, slug}] = name
+	}
+	return nil
+}
+
+// localize names slug in the configured language, as looked up by
+// resolveNames, falling back to slug.
+func (configuration *config) localize(kind pokedex.NameKind, slug string) string {
+	if configuration.output == formatJSON {
+		return slug
+	}
+	if name, resolved := configuration.names[nameKey{kind, configuration.language, slug}]; resolved {
+		return name
+	}
+	return slug
+}
+
+func (configuration *config) localizeAll(kind pokedex.NameKind, slugs []string) (names []string) {
+	names = []string{}
+	for _, slug := range slugs {
+		names = append(names, configuration.localize(kind, slug))
+	}
+	return names
 }
 
 func main() {
+	configuration := config{output: formatTable}
+	savePath, err := pokedex.DefaultSavePath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitFailure)
+	}
+	configuration.store = pokedex.NewJSONFileStore(savePath)
+	cache := newCache()
+	configuration.client = pokedex.NewClient(pokedex.DefaultBaseURL, nil, cache)
+	configuration.engine = battle.NewEngine(configuration.client.TypeChart(), nil)
+	err = pokedex.LoadPokedex(configuration.store)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		configuration.loadErr = err
+	}
 
-	configuration := config{next: "https://pokeapi.co/api/v2/location-area/?offset=0&limit=20"}
+	commands := newCommands()
+	if len(os.Args) > 1 {
+		code := runSubcommand(&configuration, commands, os.Args[1:])
+		cache.Close()
+		os.Exit(code)
+	}
+	runREPL(&configuration, commands)
+	cache.Close()
+}
 
+// newCommands returns every command, shared by the REPL and one-shot mode.
+func newCommands() map[string]cliCommand {
 	commands := map[string]cliCommand{
 		"exit": {
 			name:        "exit",
@@ -42,17 +174,31 @@ func main() {
 		},
 		"explore": {
 			name:        "explore",
-			description: "explore <map> Explores avaialble Pokémon in provided map.",
+			description: "Explores avaialble Pokémon in provided map.",
+			args:        argSpec{required: []string{"map"}},
+			complete:    []completionSource{completeLocations},
 			callback:    exploreMap,
 		},
 		"catch": {
 			name:        "catch",
-			description: "Throw a pokéball to a pokémon, as possibly catch it.",
-			callback:    catchPokemon,
+			description: "Throw a ball (pokeball, greatball, ultraball, masterball) at a pokémon in the explored area, and possibly catch it.",
+			args: argSpec{
+				required: []string{"pokemon"},
+				optional: []string{"ball", "status"},
+				flags: []flagSpec{
+					{name: "hp", value: "percent"},
+					{name: "area", value: "area"},
+				},
+			},
+			complete:          []completionSource{completeExplored, completeBalls, completeStatuses},
+			changesCollection: true,
+			callback:          catchPokemon,
 		},
 		"inspect": {
 			name:        "inspect",
-			description: "Inspect a caught pokemon.",
+			description: "Inspect a caught pokemon by id, nickname or name.",
+			args:        argSpec{required: []string{"pokemon"}},
+			complete:    []completionSource{completeCaught},
 			callback:    inspectPokemon,
 		},
 		"pokedex": {
@@ -60,38 +206,191 @@ func main() {
 			description: "List caught pokemons.",
 			callback:    viewPokedex,
 		},
+		"moves": {
+			name:        "moves",
+			description: "Lists the moves a pokemon can learn, optionally for one game and learn method, with --details fetching power, accuracy and PP. #N is caught pokemon N, a bare N the national dex number.",
+			args: argSpec{
+				required: []string{"pokemon"},
+				flags: []flagSpec{
+					{name: "game", value: "version-group"},
+					{name: "method", value: "level-up|machine|egg|tutor"},
+					{name: "details"},
+				},
+			},
+			complete: []completionSource{completeKnown},
+			callback: movesPokemon,
+		},
+		"ability": {
+			name:        "ability",
+			description: "Describes an ability and lists the pokemons that have it.",
+			args:        argSpec{required: []string{"ability"}},
+			complete:    []completionSource{completeAbilities},
+			callback:    abilityDetails,
+		},
+		"move": {
+			name:        "move",
+			description: "Describes a move and lists the pokemons that learn it.",
+			args:        argSpec{required: []string{"move"}},
+			complete:    []completionSource{completeMoves},
+			callback:    moveDetails,
+		},
+		"party": {
+			name:        "party",
+			description: "List the pokemons in your party.",
+			callback:    viewParty,
+		},
+		"deposit": {
+			name:              "deposit",
+			description:       "Sends a pokemon to a PC box, the first one with room unless a box number is given.",
+			args:              argSpec{required: []string{"pokemon"}, optional: []string{"box"}},
+			complete:          []completionSource{completeCaught},
+			changesCollection: true,
+			callback:          depositPokemon,
+		},
+		"withdraw": {
+			name:              "withdraw",
+			description:       "Moves a pokemon from its PC box into your party.",
+			args:              argSpec{required: []string{"pokemon"}},
+			complete:          []completionSource{completeCaught},
+			changesCollection: true,
+			callback:          withdrawPokemon,
+		},
+		"swap": {
+			name:              "swap",
+			description:       "Swaps the places of two caught pokemons, in the party or the PC boxes.",
+			args:              argSpec{required: []string{"first", "second"}},
+			complete:          []completionSource{completeCaught, completeCaught},
+			changesCollection: true,
+			callback:          swapPokemon,
+		},
+		"release": {
+			name:              "release",
+			description:       "Releases a caught pokemon for good.",
+			args:              argSpec{required: []string{"pokemon"}},
+			complete:          []completionSource{completeCaught},
+			changesCollection: true,
+			callback:          releasePokemon,
+		},
+		"battle": {
+			name:              "battle",
+			description:       "Battles a caught pokemon against a wild one.",
+			args:              argSpec{required: []string{"mine", "wild"}},
+			complete:          []completionSource{completeCaught, completeExplored},
+			changesCollection: true,
+			callback:          battlePokemon,
+		},
+		"weakness": {
+			name:        "weakness",
+			description: "Lists the type matchups against a pokemon.",
+			args:        argSpec{required: []string{"pokemon"}},
+			complete:    []completionSource{completeKnown},
+			callback:    weaknessPokemon,
+		},
+		"set": {
+			name:        "set",
+			description: "Changes a setting, e.g. set output json|csv|table or set lang ja|de|fr|slug.",
+			args:        argSpec{required: []string{"setting", "value"}},
+			complete:    []completionSource{completeSettings, completeSettingValues},
+			callback:    setOption,
+		},
+		"cache": {
+			name:        "cache",
+			description: "Shows how the response cache is doing, e.g. cache stats.",
+			args:        argSpec{required: []string{"action"}},
+			complete:    []completionSource{completeCacheActions},
+			callback:    cacheCommand,
+		},
+		"species": {
+			name:        "species",
+			description: "Shows species details of a pokemon.",
+			args:        argSpec{required: []string{"pokemon"}},
+			complete:    []completionSource{completeKnown},
+			callback:    speciesPokemon,
+		},
+		"evolution": {
+			name:        "evolution",
+			description: "Shows the evolution tree of a pokemon and how each stage is reached.",
+			args:        argSpec{required: []string{"pokemon"}},
+			complete:    []completionSource{completeKnown},
+			callback:    evolutionPokemon,
+		},
+		"evolve": {
+			name:              "evolve",
+			description:       "Evolves a caught pokemon if it meets the conditions, optionally using an item or trading it.",
+			args:              argSpec{required: []string{"pokemon"}, flags: []flagSpec{{name: "item", value: "item"}, {name: "trade"}}},
+			complete:          []completionSource{completeCaught},
+			changesCollection: true,
+			callback:          evolvePokemon,
+		},
+		"nickname": {
+			name:              "nickname",
+			description:       "Nicknames a caught pokemon, or removes its nickname when none is given. Quote names to keep their case.",
+			args:              argSpec{required: []string{"pokemon"}, optional: []string{"nickname"}},
+			complete:          []completionSource{completeCaught},
+			changesCollection: true,
+			callback:          nicknamePokemon,
+		},
+		"give": {
+			name:              "give",
+			description:       "Gives a caught pokemon an item to hold, or takes it back when no item is named.",
+			args:              argSpec{required: []string{"pokemon"}, optional: []string{"item"}},
+			complete:          []completionSource{completeCaught},
+			changesCollection: true,
+			callback:          givePokemon,
+		},
+		"save": {
+			name:        "save",
+			description: "Save caught pokemons to disk. --force replaces a save file that failed to load.",
+			args:        argSpec{flags: []flagSpec{{name: "force"}}},
+			callback:    savePokedex,
+		},
+		"load": {
+			name:        "load",
+			description: "Load caught pokemons from disk, replacing the current session.",
+			callback:    loadPokedex,
+		},
 	}
 	commands["help"] = cliCommand{
 		name:        "help",
 		description: "Displays a help message",
 		callback:    makeHelpCommand(commands),
 	}
+	return commands
+}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for {
-		fmt.Print("Pokedex > ")
-		if !scanner.Scan() {
-			return
-		}
-		err := scanner.Err()
-		if err != nil {
-			error := fmt.Errorf("Error Scanning %w", err)
-			fmt.Println(error)
-			return
-		}
-		data := scanner.Text()
-		dataElements := pokedex.CleanInput(data)
-		if len(dataElements) == 0 {
-			continue
-		}
-		command := dataElements[0]
-		if len(dataElements) > 1 {
-			configuration.variable = dataElements[1]
-		}
-		if executeCommand, exists := commands[command]; exists {
-			executeCommand.callback(&configuration)
-		} else {
-			fmt.Println("Unknown command")
-		}
+// memoryCacheDuration bounds how long responses are held in memory,
+// while diskCacheDuration is long enough that areas and Pokémon explored in
+// one session are still available offline in the next.
+const (
+	memoryCacheDuration = 2 * time.Minute
+	diskCacheDuration   = 24 * time.Hour
+)
+
+// memoryCacheEntries and memoryCacheBytes bound the in-memory tier; the least
+// recently used responses beyond them are still on disk.
+const (
+	memoryCacheEntries = 2000
+	memoryCacheBytes   = 64 << 20
+)
+
+// newCache prefers a disk-backed cache, falling back to memory only.
+func newCache() *pokecache.Cache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Disk cache disabled:", err)
+		return newMemoryCache()
 	}
+	cache, err := pokecache.NewDiskCache(memoryCacheDuration, diskCacheDuration, filepath.Join(dir, "pokedex"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Disk cache disabled:", err)
+		return newMemoryCache()
+	}
+	cache.SetLimits(memoryCacheEntries, memoryCacheBytes)
+	return cache
+}
+
+func newMemoryCache() *pokecache.Cache {
+	cache := pokecache.NewCache(memoryCacheDuration)
+	cache.SetLimits(memoryCacheEntries, memoryCacheBytes)
+	return cache
 }
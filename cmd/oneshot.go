@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// Exit codes used in one-shot mode.
+const (
+	exitSuccess     = 0
+	exitFailure     = 1
+	exitUsage       = 2
+	exitInterrupted = 130
+)
+
+// runSubcommand runs a single command given on the command line, e.g.
+// `pokedex explore canalave-city-area --json`, and returns the exit code.
+func runSubcommand(configuration *config, commands map[string]cliCommand, argv []string) int {
+	argv = extractGlobalFlags(configuration, argv)
+	if len(argv) == 0 {
+		fmt.Fprintln(os.Stderr, "missing command, run `pokedex help` for a list")
+		return exitUsage
+	}
+	command, exists := commands[strings.ToLower(argv[0])]
+	if !exists {
+		fmt.Fprintf(os.Stderr, "unknown command %q, run `pokedex help` for a list\n", argv[0])
+		return exitUsage
+	}
+	args, err := command.args.parse(command.name, argvTokens(argv[1:]))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err = command.callback(ctx, configuration, args)
+	if command.changesCollection {
+		autosave(configuration)
+	}
+	switch {
+	case err == nil:
+		return exitSuccess
+	case ctx.Err() != nil:
+		fmt.Fprintln(os.Stderr, "interrupted")
+		return exitInterrupted
+	case errors.As(err, new(*usageError)):
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	fmt.Fprintln(os.Stderr, err)
+	return exitFailure
+}
+
+// extractGlobalFlags removes flags that apply to every command, --json and
+// --csv, and records them in configuration.
+func extractGlobalFlags(configuration *config, argv []string) (rest []string) {
+	rest = []string{}
+	for i, arg := range argv {
+		if arg == "--" {
+			return append(rest, argv[i:]...)
+		}
+		switch arg {
+		case "--json":
+			configuration.output = formatJSON
+			continue
+		case "--csv":
+			configuration.output = formatCSV
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest
+}
+
+// argvTokens converts shell arguments to tokens. The shell has already done
+// the quoting, so an argument containing whitespace is treated as quoted and
+// keeps its case; everything else is lowercased like REPL input.
+func argvTokens(argv []string) (tokens []token) {
+	tokens = []token{}
+	for _, arg := range argv {
+		if strings.ContainsAny(arg, " \t") {
+			tokens = append(tokens, token{text: arg, quoted: true})
+			continue
+		}
+		tokens = append(tokens, token{text: strings.ToLower(arg)})
+	}
+	return tokens
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pokedex "github.com/anantashahane/pokedex/pokedex"
+)
+
+func TestExtractGlobalFlags(t *testing.T) {
+	configuration := config{}
+	rest := extractGlobalFlags(&configuration, []string{"explore", "--json", "canalave-city-area", "--", "--json"})
+	if configuration.output != formatJSON {
+		t.Errorf("Expected --json to be recorded")
+	}
+	expected := []string{"explore", "canalave-city-area", "--", "--json"}
+	if len(rest) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, rest)
+	}
+	for i := range expected {
+		if rest[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], rest[i])
+		}
+	}
+}
+
+func TestArgvTokens(t *testing.T) {
+	tokens := argvTokens([]string{"Pikachu", "Sir Sparks", "--hp"})
+	if tokens[0].text != "pikachu" || tokens[0].quoted {
+		t.Errorf("Expected a lowercased bare word, got %+v", tokens[0])
+	}
+	if tokens[1].text != "Sir Sparks" || !tokens[1].quoted {
+		t.Errorf("Expected a quoted phrase keeping its case, got %+v", tokens[1])
+	}
+	if tokens[2].quoted {
+		t.Errorf("Expected flags to stay unquoted")
+	}
+}
+
+func TestReadOnlyCommandDoesNotSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	configuration := config{output: formatJSON, store: pokedex.NewJSONFileStore(path)}
+	if code := runSubcommand(&configuration, newCommands(), []string{"pokedex"}); code != exitSuccess {
+		t.Fatalf("got exit code %d, want %d", code, exitSuccess)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected a read-only command to leave the save file alone, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	formatTable outputFormat = "table"
+	formatJSON  outputFormat = "json"
+	formatCSV   outputFormat = "csv"
+)
+
+var outputFormats = []outputFormat{formatTable, formatJSON, formatCSV}
+
+func parseOutputFormat(value string) (format outputFormat, err error) {
+	format = outputFormat(value)
+	if !slices.Contains(outputFormats, format) {
+		return "", fmt.Errorf("unknown output format %q, expected table, json or csv", value)
+	}
+	return format, nil
+}
+
+// output is what a command produces. Rows are used for table and CSV output;
+// value, when set, replaces them as the JSON document.
+type output struct {
+	columns []string
+	rows    [][]string
+	value   any
+	// text optionally replaces the generic table with hand written output.
+	text func(w io.Writer)
+}
+
+func (configuration *config) render(out output) error {
+	return render(os.Stdout, configuration.output, out)
+}
+
+func render(w io.Writer, format outputFormat, out output) error {
+	switch format {
+	case formatJSON:
+		return renderJSON(w, out)
+	case formatCSV:
+		return renderCSV(w, out)
+	}
+	if out.text != nil {
+		out.text(w)
+		return nil
+	}
+	return renderTable(w, out)
+}
+
+func renderJSON(w io.Writer, out output) error {
+	value := out.value
+	if value == nil {
+		records := []map[string]string{}
+		for _, row := range out.rows {
+			record := map[string]string{}
+			for i, column := range out.columns {
+				record[column] = row[i]
+			}
+			records = append(records, record)
+		}
+		value = records
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(value)
+}
+
+func renderCSV(w io.Writer, out output) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(out.columns)
+	if err != nil {
+		return err
+	}
+	err = writer.WriteAll(out.rows)
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// renderTable keeps the REPL's tab indented look. Single column output is
+// shown as a plain list without a header.
+func renderTable(w io.Writer, out output) error {
+	writer := tabwriter.NewWriter(w, 0, 8, 1, '\t', 0)
+	if len(out.columns) > 1 {
+		fmt.Fprintln(writer, "\t"+strings.Join(out.columns, "\t"))
+	}
+	for _, row := range out.rows {
+		fmt.Fprintln(writer, "\t"+strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	out := output{
+		columns: []string{"type", "multiplier"},
+		rows:    [][]string{{"ground", "2"}, {"flying", "0.5"}},
+	}
+	cases := []struct {
+		format   outputFormat
+		expected string
+	}{
+		{format: formatCSV, expected: "type,multiplier\nground,2\nflying,0.5\n"},
+		{format: formatJSON, expected: "[\n  {\n    \"multiplier\": \"2\",\n    \"type\": \"ground\"\n  },\n  {\n    \"multiplier\": \"0.5\",\n    \"type\": \"flying\"\n  }\n]\n"},
+		{format: formatTable, expected: "\ttype\tmultiplier\n\tground\t2\n\tflying\t0.5\n"},
+	}
+	for _, c := range cases {
+		var buffer bytes.Buffer
+		err := render(&buffer, c.format, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buffer.String() != c.expected {
+			t.Errorf("Expected %s output %q, got %q", c.format, c.expected, buffer.String())
+		}
+	}
+}
+
+func TestRenderPrefersValueAndText(t *testing.T) {
+	out := output{
+		columns: []string{"name"},
+		rows:    [][]string{{"pikachu"}},
+		value:   []string{"pikachu"},
+		text: func(w io.Writer) {
+			io.WriteString(w, "custom\n")
+		},
+	}
+	var buffer bytes.Buffer
+	render(&buffer, formatJSON, out)
+	if buffer.String() != "[\n  \"pikachu\"\n]\n" {
+		t.Errorf("Expected value to be rendered as JSON, got %q", buffer.String())
+	}
+	buffer.Reset()
+	render(&buffer, formatTable, out)
+	if buffer.String() != "custom\n" {
+		t.Errorf("Expected custom text in table mode, got %q", buffer.String())
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	lineedit "github.com/anantashahane/pokedex/lineedit"
+	pokedex "github.com/anantashahane/pokedex/pokedex"
+)
+
+func runREPL(configuration *config, commands map[string]cliCommand) {
+	// Ctrl-C aborts the command in flight instead of killing the REPL.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	configuration.prefetch = true
+	defer configuration.cancelPrefetch()
+
+	editor := lineedit.New(os.Stdin, os.Stdout)
+	editor.SetCompleter(newCompleter(configuration, commands))
+	if path, err := historyPath(); err == nil {
+		err = editor.LoadHistory(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+	for {
+		line, err := editor.ReadLine("Pokedex > ")
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			autosave(configuration)
+			return
+		}
+		if err != nil {
+			error := fmt.Errorf("Error Scanning %w", err)
+			fmt.Println(error)
+			return
+		}
+		err = editor.AddHistory(line)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		tokens, err := tokenize(line)
+		if err != nil {
+			fmt.Println("\t" + err.Error())
+			continue
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		executeCommand, exists := commands[tokens[0].text]
+		if !exists {
+			fmt.Println("Unknown command")
+			continue
+		}
+		args, err := executeCommand.args.parse(executeCommand.name, tokens[1:])
+		if err == nil {
+			err = runCommand(executeCommand, configuration, args, interrupts)
+		}
+		if err != nil {
+			fmt.Println("\t" + err.Error())
+		}
+	}
+}
+
+// runCommand executes command with a context that is cancelled on interrupt.
+func runCommand(command cliCommand, configuration *config, args arguments, interrupts <-chan os.Signal) error {
+	// Discard any Ctrl-C pressed while idle at the prompt.
+	select {
+	case <-interrupts:
+	default:
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupts:
+			fmt.Println("\n\tInterrupted.")
+			cancel()
+		case <-done:
+		}
+	}()
+	err := command.callback(ctx, configuration, args)
+	close(done)
+	cancel()
+	return err
+}
+
+// historyPath is the dotfile REPL history is kept in.
+func historyPath() (path string, err error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".pokedex_history"), nil
+}
+
+// completionSource lists the values an argument can take in the current session.
+type completionSource func(configuration *config) []string
+
+func completeLocations(configuration *config) []string {
+	return configuration.locations
+}
+
+func completeExplored(configuration *config) []string {
+	return configuration.explored
+}
+
+// completeCaught offers the nicknames and Pokémon names in the collection.
+func completeCaught(configuration *config) (references []string) {
+	references = []string{}
+	for _, caught := range pokedex.ViewPokedex() {
+		for _, reference := range []string{caught.Nickname, caught.Pokemon.Name} {
+			if reference != "" && !slices.Contains(references, reference) {
+				references = append(references, reference)
+			}
+		}
+	}
+	slices.Sort(references)
+	return references
+}
+
+// completeKnown offers every Pokémon seen this session, explored or caught.
+func completeKnown(configuration *config) []string {
+	known := slices.Clone(configuration.explored)
+	for _, caught := range pokedex.ViewPokedex() {
+		if !slices.Contains(known, caught.Pokemon.Name) {
+			known = append(known, caught.Pokemon.Name)
+		}
+	}
+	return known
+}
+
+// completeAbilities offers the abilities of caught Pokémon.
+func completeAbilities(configuration *config) []string {
+	abilities := map[string]bool{}
+	for _, caught := range pokedex.ViewPokedex() {
+		for _, ability := range caught.Pokemon.Abilities {
+			abilities[ability.Ability.Name] = true
+		}
+	}
+	return sortedKeys(abilities)
+}
+
+// completeMoves offers the moves caught Pokémon can learn.
+func completeMoves(configuration *config) []string {
+	moves := map[string]bool{}
+	for _, caught := range pokedex.ViewPokedex() {
+		for _, move := range caught.Pokemon.Moves {
+			moves[move.Move.Name] = true
+		}
+	}
+	return sortedKeys(moves)
+}
+
+func completeBalls(configuration *config) []string {
+	return sortedKeys(pokedex.BallModifiers)
+}
+
+func completeStatuses(configuration *config) []string {
+	return slices.DeleteFunc(sortedKeys(pokedex.StatusModifiers), func(status string) bool {
+		return status == ""
+	})
+}
+
+func completeSettings(configuration *config) []string {
+	return []string{"lang", "output"}
+}
+
+func completeCacheActions(configuration *config) []string {
+	return []string{"stats"}
+}
+
+// completeSettingValues offers the values of every setting, as sources do not
+// see the setting named before them.
+func completeSettingValues(configuration *config) (values []string) {
+	values = []string{}
+	for _, format := range outputFormats {
+		values = append(values, string(format))
+	}
+	values = append(values, "slug")
+	for _, language := range pokedex.Languages {
+		values = append(values, strings.ToLower(language))
+	}
+	return values
+}
+
+func sortedKeys[V any](m map[string]V) (keys []string) {
+	keys = []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// newCompleter completes command names, then each positional argument from
+// the command's completion sources. Flags and their values are skipped.
+func newCompleter(configuration *config, commands map[string]cliCommand) lineedit.Completer {
+	return func(words []string, partial string) []string {
+		if len(words) == 0 {
+			return sortedKeys(commands)
+		}
+		command, exists := commands[strings.ToLower(words[0])]
+		if !exists {
+			return []string{}
+		}
+		position := 0
+		for i := 1; i < len(words); i++ {
+			if !strings.HasPrefix(words[i], "--") {
+				position++
+				continue
+			}
+			flag, exists := command.args.lookupFlag(strings.TrimPrefix(words[i], "--"))
+			if exists && flag.value != "" && !strings.Contains(words[i], "=") {
+				i++
+			}
+		}
+		if position >= len(command.complete) || command.complete[position] == nil {
+			return []string{}
+		}
+		return command.complete[position](configuration)
+	}
+}
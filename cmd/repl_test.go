@@ -0,0 +1,33 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompleter(t *testing.T) {
+	configuration := &config{
+		locations: []string{"canalave-city-area"},
+		explored:  []string{"tentacool", "staryu"},
+	}
+	complete := newCompleter(configuration, newCommands())
+
+	if candidates := complete([]string{}, "ca"); !slices.Contains(candidates, "catch") {
+		t.Errorf("Expected command names, got %v", candidates)
+	}
+	if candidates := complete([]string{"explore"}, ""); !slices.Equal(candidates, configuration.locations) {
+		t.Errorf("Expected the last map page, got %v", candidates)
+	}
+	if candidates := complete([]string{"catch"}, "st"); !slices.Equal(candidates, configuration.explored) {
+		t.Errorf("Expected the last explore result, got %v", candidates)
+	}
+	if candidates := complete([]string{"catch", "--hp", "50", "staryu"}, ""); !slices.Contains(candidates, "ultraball") {
+		t.Errorf("Expected balls after skipping flags, got %v", candidates)
+	}
+	if candidates := complete([]string{"set", "lang"}, ""); !slices.Contains(candidates, "ja-hrkt") {
+		t.Errorf("Expected language codes as setting values, got %v", candidates)
+	}
+	if candidates := complete([]string{"map"}, ""); len(candidates) != 0 {
+		t.Errorf("Expected no candidates for a command without arguments, got %v", candidates)
+	}
+}
@@ -0,0 +1,455 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted is returned by ReadLine when Ctrl-C is pressed at the prompt.
+var ErrInterrupted = errors.New("interrupted")
+
+// MaxHistory bounds the number of lines kept in memory and in the history file.
+const MaxHistory = 1000
+
+// Completer returns candidates for the word being typed. words holds the
+// completed words before it and partial the text typed so far.
+type Completer func(words []string, partial string) (candidates []string)
+
+// Editor reads lines with cursor movement, history and tab completion when
+// attached to a terminal, and plain lines otherwise.
+type Editor struct {
+	fd          int
+	raw         bool
+	reader      *bufio.Reader
+	out         io.Writer
+	history     []string
+	historyPath string
+	completer   Completer
+	// fileLines counts the lines in the history file, so it can be trimmed
+	// once appending would take it past MaxHistory.
+	fileLines int
+}
+
+// New returns an Editor reading from in. Editing is only enabled when in is a terminal.
+func New(in *os.File, out io.Writer) *Editor {
+	fd := int(in.Fd())
+	editor := newEditor(in, out, term.IsTerminal(fd))
+	editor.fd = fd
+	return editor
+}
+
+func newEditor(in io.Reader, out io.Writer, raw bool) *Editor {
+	return &Editor{fd: -1, raw: raw, reader: bufio.NewReader(in), out: out, history: []string{}}
+}
+
+func (editor *Editor) SetCompleter(completer Completer) {
+	editor.completer = completer
+}
+
+// LoadHistory reads previous lines from path, which is created if missing.
+// Lines later passed to AddHistory are appended to the same file.
+func (editor *Editor) LoadHistory(path string) error {
+	editor.historyPath = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading history file %s: %w", path, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			editor.history = append(editor.history, line)
+		}
+	}
+	editor.fileLines = len(editor.history)
+	if len(editor.history) > MaxHistory {
+		editor.history = editor.history[len(editor.history)-MaxHistory:]
+		return editor.rewriteHistory()
+	}
+	return nil
+}
+
+// AddHistory records line, skipping blanks and immediate repeats.
+func (editor *Editor) AddHistory(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(editor.history) > 0 && editor.history[len(editor.history)-1] == line) {
+		return nil
+	}
+	editor.history = append(editor.history, line)
+	if len(editor.history) > MaxHistory {
+		editor.history = editor.history[1:]
+	}
+	if editor.historyPath == "" {
+		return nil
+	}
+	if editor.fileLines >= MaxHistory {
+		return editor.rewriteHistory()
+	}
+	file, err := os.OpenFile(editor.historyPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("Error opening history file %s: %w", editor.historyPath, err)
+	}
+	defer file.Close()
+	_, err = fmt.Fprintln(file, line)
+	if err == nil {
+		editor.fileLines++
+	}
+	return err
+}
+
+// rewriteHistory replaces the history file with the lines kept in memory.
+func (editor *Editor) rewriteHistory() error {
+	data := strings.Join(editor.history, "\n") + "\n"
+	err := os.WriteFile(editor.historyPath, []byte(data), 0o600)
+	if err != nil {
+		return fmt.Errorf("Error writing history file %s: %w", editor.historyPath, err)
+	}
+	editor.fileLines = len(editor.history)
+	return nil
+}
+
+// ReadLine shows prompt and returns the next line without its newline. It
+// returns io.EOF on Ctrl-D at an empty prompt or end of input.
+func (editor *Editor) ReadLine(prompt string) (line string, err error) {
+	if !editor.raw {
+		return editor.readPlain(prompt)
+	}
+	if editor.fd >= 0 {
+		state, err := term.MakeRaw(editor.fd)
+		if err != nil {
+			return editor.readPlain(prompt)
+		}
+		defer term.Restore(editor.fd, state)
+	}
+	return editor.readRaw(prompt)
+}
+
+func (editor *Editor) readPlain(prompt string) (line string, err error) {
+	fmt.Fprint(editor.out, prompt)
+	line, err = editor.reader.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Key codes understood by readRaw.
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlG     = 7
+	keyBackspace = 8
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlR     = 18
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// Pseudo keys for escape sequences, outside the rune range used by text.
+const (
+	keyUp = -iota - 1
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyForwardDelete
+	keyUnknown
+)
+
+// lineState is the line being edited.
+type lineState struct {
+	prompt  string
+	buffer  []rune
+	cursor  int
+	history int
+	// pending is the unsubmitted line, restored when scrolling past the newest history entry.
+	pending  []rune
+	tabCount int
+}
+
+func (editor *Editor) readRaw(prompt string) (line string, err error) {
+	state := &lineState{prompt: prompt, buffer: []rune{}, history: len(editor.history)}
+	editor.refresh(state)
+	for {
+		key, err := editor.readKey()
+		if err != nil {
+			editor.write("\r\n")
+			return "", err
+		}
+		if key == keyTab {
+			state.tabCount++
+		} else {
+			state.tabCount = 0
+		}
+		switch key {
+		case keyEnter, '\n':
+			editor.write("\r\n")
+			return string(state.buffer), nil
+		case keyCtrlC:
+			editor.write("^C\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(state.buffer) == 0 {
+				editor.write("\r\n")
+				return "", io.EOF
+			}
+			state.deleteForward()
+		case keyBackspace, keyDelete:
+			if state.cursor > 0 {
+				state.buffer = slices.Delete(state.buffer, state.cursor-1, state.cursor)
+				state.cursor--
+			}
+		case keyForwardDelete:
+			state.deleteForward()
+		case keyLeft, keyCtrlB:
+			state.cursor = max(state.cursor-1, 0)
+		case keyRight, keyCtrlF:
+			state.cursor = min(state.cursor+1, len(state.buffer))
+		case keyHome, keyCtrlA:
+			state.cursor = 0
+		case keyEnd, keyCtrlE:
+			state.cursor = len(state.buffer)
+		case keyUp, keyCtrlP:
+			editor.moveHistory(state, -1)
+		case keyDown, keyCtrlN:
+			editor.moveHistory(state, 1)
+		case keyCtrlK:
+			state.buffer = state.buffer[:state.cursor]
+		case keyCtrlU:
+			state.buffer = slices.Clone(state.buffer[state.cursor:])
+			state.cursor = 0
+		case keyCtrlW:
+			start := state.cursor
+			for start > 0 && state.buffer[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && state.buffer[start-1] != ' ' {
+				start--
+			}
+			state.buffer = slices.Delete(state.buffer, start, state.cursor)
+			state.cursor = start
+		case keyCtrlL:
+			editor.write("\x1b[H\x1b[2J")
+		case keyTab:
+			editor.complete(state)
+		case keyCtrlR:
+			submit, err := editor.reverseSearch(state)
+			if err != nil {
+				editor.write("\r\n")
+				return "", err
+			}
+			if submit {
+				editor.refresh(state)
+				editor.write("\r\n")
+				return string(state.buffer), nil
+			}
+		default:
+			if key >= ' ' {
+				state.buffer = slices.Insert(state.buffer, state.cursor, rune(key))
+				state.cursor++
+			}
+		}
+		editor.refresh(state)
+	}
+}
+
+func (state *lineState) deleteForward() {
+	if state.cursor < len(state.buffer) {
+		state.buffer = slices.Delete(state.buffer, state.cursor, state.cursor+1)
+	}
+}
+
+func (state *lineState) set(line []rune) {
+	state.buffer = slices.Clone(line)
+	state.cursor = len(state.buffer)
+}
+
+func (editor *Editor) moveHistory(state *lineState, direction int) {
+	target := state.history + direction
+	if target < 0 || target > len(editor.history) {
+		return
+	}
+	if state.history == len(editor.history) {
+		state.pending = slices.Clone(state.buffer)
+	}
+	state.history = target
+	if target == len(editor.history) {
+		state.set(state.pending)
+		return
+	}
+	state.set([]rune(editor.history[target]))
+}
+
+// complete replaces the word before the cursor with the longest common prefix
+// of its candidates. A second Tab without progress lists the candidates.
+func (editor *Editor) complete(state *lineState) {
+	if editor.completer == nil {
+		return
+	}
+	before := string(state.buffer[:state.cursor])
+	words := strings.Fields(before)
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(before, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	candidates := editor.completer(words, partial)
+	matches := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, partial) && !slices.Contains(matches, candidate) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		completion += " "
+	}
+	if completion != partial {
+		insert := []rune(strings.TrimPrefix(completion, partial))
+		state.buffer = slices.Insert(state.buffer, state.cursor, insert...)
+		state.cursor += len(insert)
+		return
+	}
+	if state.tabCount >= 2 {
+		slices.Sort(matches)
+		editor.write("\r\n" + strings.Join(matches, "  ") + "\r\n")
+	}
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// reverseSearch implements Ctrl-R. It reports whether Enter was pressed, in
+// which case the match should be submitted straight away.
+func (editor *Editor) reverseSearch(state *lineState) (submit bool, err error) {
+	original := slices.Clone(state.buffer)
+	query := []rune{}
+	match := len(editor.history)
+	find := func(from int) {
+		for i := from; i >= 0; i-- {
+			if strings.Contains(editor.history[i], string(query)) {
+				match = i
+				state.set([]rune(editor.history[i]))
+				return
+			}
+		}
+	}
+	for {
+		editor.write(fmt.Sprintf("\r(reverse-i-search)`%s': %s\x1b[K", string(query), string(state.buffer)))
+		key, err := editor.readKey()
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case key == keyCtrlR:
+			find(match - 1)
+		case key == keyBackspace || key == keyDelete:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+				find(len(editor.history) - 1)
+			}
+		case key == keyEnter || key == '\n':
+			return true, nil
+		case key == keyCtrlG || key == keyCtrlC || key == keyEscape:
+			state.set(original)
+			return false, nil
+		case key >= ' ':
+			query = append(query, rune(key))
+			find(min(match, len(editor.history)-1))
+		default:
+			// Any other editing key accepts the match and leaves search mode.
+			return false, nil
+		}
+	}
+}
+
+func (editor *Editor) readKey() (key int, err error) {
+	r, _, err := editor.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r != keyEscape {
+		return int(r), nil
+	}
+	if editor.reader.Buffered() == 0 {
+		return keyEscape, nil
+	}
+	next, _, err := editor.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if next != '[' && next != 'O' {
+		return keyUnknown, nil
+	}
+	sequence := ""
+	for {
+		r, _, err := editor.reader.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		sequence += string(r)
+		if r >= '@' && r <= '~' {
+			break
+		}
+	}
+	switch sequence {
+	case "A":
+		return keyUp, nil
+	case "B":
+		return keyDown, nil
+	case "C":
+		return keyRight, nil
+	case "D":
+		return keyLeft, nil
+	case "H", "1~", "7~":
+		return keyHome, nil
+	case "F", "4~", "8~":
+		return keyEnd, nil
+	case "3~":
+		return keyForwardDelete, nil
+	}
+	return keyUnknown, nil
+}
+
+// refresh redraws the prompt and buffer, then puts the cursor back in place.
+func (editor *Editor) refresh(state *lineState) {
+	line := "\r" + state.prompt + string(state.buffer) + "\x1b[K"
+	if back := len(state.buffer) - state.cursor; back > 0 {
+		line += fmt.Sprintf("\x1b[%dD", back)
+	}
+	editor.write(line)
+}
+
+func (editor *Editor) write(text string) {
+	io.WriteString(editor.out, text)
+}
@@ -0,0 +1,146 @@
+package lineedit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readRawLine(t *testing.T, editor *Editor, keys string) string {
+	t.Helper()
+	editor.reader.Reset(strings.NewReader(keys))
+	line, err := editor.ReadLine("> ")
+	if err != nil {
+		t.Fatalf("Unexpected error for keys %q: %v", keys, err)
+	}
+	return line
+}
+
+func TestReadLineEditing(t *testing.T) {
+	editor := newEditor(strings.NewReader(""), io.Discard, true)
+	cases := []struct {
+		keys     string
+		expected string
+	}{
+		{keys: "catch pikachu\r", expected: "catch pikachu"},
+		{keys: "pikachu\x1b[D\x1b[D\x7f\x1b[Hcatch \r", expected: "catch pikahu"},
+		{keys: "catch mew\x17inspect\r", expected: "catch inspect"},
+		{keys: "abc\x01\x0b\r", expected: ""},
+	}
+	for _, c := range cases {
+		if actual := readRawLine(t, editor, c.keys); actual != c.expected {
+			t.Errorf("Expected %q for keys %q, got %q", c.expected, c.keys, actual)
+		}
+	}
+}
+
+func TestReadLineHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".history")
+	editor := newEditor(strings.NewReader(""), io.Discard, true)
+	err := editor.LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	editor.AddHistory("explore canalave-city-area")
+	editor.AddHistory("catch tentacool")
+	editor.AddHistory("catch tentacool")
+
+	if actual := readRawLine(t, editor, "\x1b[A\x1b[A\r"); actual != "explore canalave-city-area" {
+		t.Errorf("Expected the oldest entry after two ups, got %q", actual)
+	}
+	if actual := readRawLine(t, editor, "map\x1b[A\x1b[B\r"); actual != "map" {
+		t.Errorf("Expected the unsubmitted line back after up and down, got %q", actual)
+	}
+	if actual := readRawLine(t, editor, "\x12explore\r"); actual != "explore canalave-city-area" {
+		t.Errorf("Expected reverse search to find explore, got %q", actual)
+	}
+
+	reloaded := newEditor(strings.NewReader(""), io.Discard, true)
+	err = reloaded.LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.history) != 2 {
+		t.Errorf("Expected 2 persisted entries without the repeat, got %v", reloaded.history)
+	}
+}
+
+func TestHistoryFileIsTrimmed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".history")
+	editor := newEditor(strings.NewReader(""), io.Discard, true)
+	err := editor.LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range MaxHistory + 10 {
+		err = editor.AddHistory(fmt.Sprintf("inspect %d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != MaxHistory {
+		t.Errorf("Expected the history file to hold %d lines, got %d", MaxHistory, len(lines))
+	}
+	if last := fmt.Sprintf("inspect %d", MaxHistory+9); lines[len(lines)-1] != last {
+		t.Errorf("Expected the newest line %q last, got %q", last, lines[len(lines)-1])
+	}
+}
+
+func TestReadLineCompletion(t *testing.T) {
+	editor := newEditor(strings.NewReader(""), io.Discard, true)
+	editor.SetCompleter(func(words []string, partial string) []string {
+		if len(words) == 0 {
+			return []string{"catch", "exit", "explore"}
+		}
+		return []string{"tentacool", "tentacruel"}
+	})
+	cases := []struct {
+		keys     string
+		expected string
+	}{
+		{keys: "ca\t\r", expected: "catch "},
+		{keys: "ex\t\r", expected: "ex"},
+		{keys: "catch te\t\r", expected: "catch tentac"},
+		{keys: "catch tentacr\t\r", expected: "catch tentacruel "},
+	}
+	for _, c := range cases {
+		if actual := readRawLine(t, editor, c.keys); actual != c.expected {
+			t.Errorf("Expected %q for keys %q, got %q", c.expected, c.keys, actual)
+		}
+	}
+}
+
+func TestReadLineControlKeys(t *testing.T) {
+	editor := newEditor(strings.NewReader(""), io.Discard, true)
+	editor.reader.Reset(strings.NewReader("cat\x03"))
+	if _, err := editor.ReadLine("> "); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("Expected ErrInterrupted on Ctrl-C, got %v", err)
+	}
+	editor.reader.Reset(strings.NewReader("\x04"))
+	if _, err := editor.ReadLine("> "); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF on Ctrl-D, got %v", err)
+	}
+}
+
+func TestReadLinePlain(t *testing.T) {
+	editor := newEditor(strings.NewReader("map\nexplore\r\nlast"), io.Discard, false)
+	for _, expected := range []string{"map", "explore", "last"} {
+		line, err := editor.ReadLine("> ")
+		if err != nil || line != expected {
+			t.Errorf("Expected %q, got %q, %v", expected, line, err)
+		}
+	}
+	if _, err := editor.ReadLine("> "); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF at end of input, got %v", err)
+	}
+}
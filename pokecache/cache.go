@@ -1,56 +1,384 @@
 package pokecache
 
 import (
+	"bufio"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 type Cache struct {
-	CacheData map[string]cacheEntry
-	mu        sync.Mutex
+	entries map[string]cacheEntry
+	mu      sync.Mutex
+	// duration bounds how long an entry stays in memory, and diskDuration
+	// how long it is kept on disk unless added with its own TTL.
+	duration     time.Duration
+	diskDuration time.Duration
+	dir          string
+	// stop ends the reap loop; it is called by Close.
+	stop context.CancelFunc
+	// recency orders the keys of entries from most to least recently used.
+	recency    *list.List
+	bytes      int
+	maxEntries int
+	maxBytes   int
+	stats      Stats
 }
 
+// cacheEntry was created at createdAt and held in memory since storedAt,
+// which is later for an entry read back from disk.
 type cacheEntry struct {
 	createdAt time.Time
+	storedAt  time.Time
+	ttl       time.Duration
 	val       []byte
+	element   *list.Element
 }
 
+// stale reports whether entry has outlived its TTL, in memory or on disk.
+func (entry cacheEntry) stale() bool {
+	return time.Since(entry.createdAt) > entry.ttl
+}
+
+// expired reports whether entry must leave memory: it is stale, or it has
+// been in memory for longer than the cache's duration.
+func (cache *Cache) expired(entry cacheEntry) bool {
+	return entry.stale() || time.Since(entry.storedAt) > cache.duration
+}
+
+// Stats counts how the in-memory tier of a Cache has been used. Disk hits
+// count as hits; evictions are entries dropped to stay within the limits,
+// while expirations are entries that outlived the cache duration.
+type Stats struct {
+	Hits        int `json:"hits"`
+	Misses      int `json:"misses"`
+	Evictions   int `json:"evictions"`
+	Expirations int `json:"expirations"`
+	Entries     int `json:"entries"`
+	Bytes       int `json:"bytes"`
+	MaxEntries  int `json:"max_entries"`
+	MaxBytes    int `json:"max_bytes"`
+}
+
+// NewCache returns an in-memory Cache whose entries expire after duration, or
+// their own shorter TTL. Call Close to stop its reap loop.
 func NewCache(duration time.Duration) *Cache {
-	cache := &Cache{CacheData: map[string]cacheEntry{}, mu: sync.Mutex{}}
-	go cache.reapLoop(duration)
+	return NewCacheContext(context.Background(), duration)
+}
+
+// NewCacheContext is NewCache with a reap loop that also stops when ctx is done.
+func NewCacheContext(ctx context.Context, duration time.Duration) *Cache {
+	cache := &Cache{entries: map[string]cacheEntry{}, mu: sync.Mutex{}, duration: duration, diskDuration: duration, recency: list.New()}
+	cache.start(ctx)
 	return cache
 }
 
-func (cache *Cache) Add(key string, val []byte) {
-	entry := cacheEntry{createdAt: time.Now(), val: val}
+// NewDiskCache returns a Cache that also writes entries under dir, so they
+// survive restarts. Entries stay in memory for duration and on disk for
+// diskDuration; a lookup after the first reads them back from disk.
+func NewDiskCache(duration, diskDuration time.Duration, dir string) (*Cache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating cache directory %s: %w", dir, err)
+	}
+	cache := &Cache{entries: map[string]cacheEntry{}, mu: sync.Mutex{}, duration: duration, diskDuration: diskDuration, dir: dir, recency: list.New()}
+	cache.start(context.Background())
+	return cache, nil
+}
 
+func (cache *Cache) start(ctx context.Context) {
+	ctx, cache.stop = context.WithCancel(ctx)
+	go cache.reapLoop(ctx, cache.duration)
+}
+
+// Close stops the reap loop. The cache keeps serving entries afterwards, but
+// expired ones are only dropped when looked up. Close may be called repeatedly.
+func (cache *Cache) Close() {
+	cache.stop()
+}
+
+// SetLimits bounds the in-memory tier to maxEntries entries and maxBytes bytes
+// of values, evicting the least recently used entries beyond them. Zero means
+// no limit. Evicted entries stay on disk for a disk cache.
+func (cache *Cache) SetLimits(maxEntries, maxBytes int) {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
+	cache.maxEntries, cache.maxBytes = maxEntries, maxBytes
+	cache.evict()
+}
 
-	cache.CacheData[key] = entry
+// Stats returns the usage counters along with the current size and limits.
+func (cache *Cache) Stats() Stats {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	stats := cache.stats
+	stats.Entries = len(cache.entries)
+	stats.Bytes = cache.bytes
+	stats.MaxEntries = cache.maxEntries
+	stats.MaxBytes = cache.maxBytes
+	return stats
+}
+
+// MemoryDuration is how long entries stay in memory, which caches layered in
+// front of this one should not exceed.
+func (cache *Cache) MemoryDuration() time.Duration {
+	return cache.duration
+}
+
+// Add stores val under key for the cache's disk duration, or its duration
+// for an in-memory cache.
+func (cache *Cache) Add(key string, val []byte) {
+	cache.AddWithTTL(key, val, 0)
+}
+
+// AddWithTTL stores val under key until ttl has passed. A zero ttl means the
+// cache's disk duration. Memory only keeps it for the cache's duration, so a
+// longer ttl only pays off with a disk tier.
+func (cache *Cache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = cache.diskDuration
+	}
+	now := time.Now()
+	entry := cacheEntry{createdAt: now, storedAt: now, ttl: ttl, val: val}
+
+	cache.mu.Lock()
+	cache.store(key, entry)
+	cache.mu.Unlock()
+
+	cache.writeDisk(key, val, ttl)
 }
 
 func (cache *Cache) Get(key string) (data []byte, available bool) {
+	data, _, available = cache.GetWithAge(key)
+	return data, available
+}
+
+// GetWithAge is Get that also reports how long ago the value was added, which
+// for a disk entry may be in an earlier session.
+func (cache *Cache) GetWithAge(key string) (data []byte, age time.Duration, available bool) {
+	cache.mu.Lock()
+	entry, available := cache.entries[key]
+	if available && cache.expired(entry) {
+		cache.remove(key)
+		cache.stats.Expirations++
+		available = false
+	}
+	if available {
+		cache.stats.Hits++
+		cache.recency.MoveToFront(entry.element)
+		cache.mu.Unlock()
+		return entry.val, time.Since(entry.createdAt), true
+	}
+	cache.mu.Unlock()
+
+	// The disk is read without holding the lock, so lookups of other keys
+	// do not wait for it.
+	entry, available = cache.readDisk(key)
+
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
-	cacheEntry, available := cache.CacheData[key]
 	if !available {
-		return []byte{}, available
+		cache.stats.Misses++
+		return []byte{}, 0, false
+	}
+	cache.stats.Hits++
+	if current, exists := cache.entries[key]; exists {
+		// An Add while the disk was read wins over the older disk copy.
+		return current.val, time.Since(current.createdAt), true
+	}
+	cache.store(key, entry)
+	return entry.val, time.Since(entry.createdAt), true
+}
+
+// store must be called with cache.mu held. It makes key the most recently
+// used entry and evicts others as needed to stay within the limits.
+func (cache *Cache) store(key string, entry cacheEntry) {
+	cache.remove(key)
+	entry.element = cache.recency.PushFront(key)
+	cache.entries[key] = entry
+	cache.bytes += len(entry.val)
+	cache.evict()
+}
+
+// remove must be called with cache.mu held.
+func (cache *Cache) remove(key string) {
+	entry, exists := cache.entries[key]
+	if !exists {
+		return
 	}
-	return cacheEntry.val, available
+	cache.recency.Remove(entry.element)
+	cache.bytes -= len(entry.val)
+	delete(cache.entries, key)
 }
 
-func (cache *Cache) reapLoop(duration time.Duration) {
+// evict must be called with cache.mu held. The most recently used entry is
+// always kept, even when it alone exceeds maxBytes.
+func (cache *Cache) evict() {
+	for cache.recency.Len() > 1 && cache.overLimits() {
+		cache.remove(cache.recency.Back().Value.(string))
+		cache.stats.Evictions++
+	}
+}
+
+func (cache *Cache) overLimits() bool {
+	return (cache.maxEntries > 0 && len(cache.entries) > cache.maxEntries) ||
+		(cache.maxBytes > 0 && cache.bytes > cache.maxBytes)
+}
+
+// diskPath maps a key to a file name; keys are URLs, so they are hashed
+// rather than used verbatim.
+func (cache *Cache) diskPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(cache.dir, hex.EncodeToString(sum[:]))
+}
+
+// diskHeader starts every disk entry, followed by its TTL in nanoseconds and a
+// newline. Files written before per-entry TTLs have no header and use the
+// cache's disk duration.
+const diskHeader = "pokecache-ttl "
+
+// writeDisk is best effort: a failed write only costs a future network fetch.
+func (cache *Cache) writeDisk(key string, val []byte, ttl time.Duration) {
+	if cache.dir == "" {
+		return
+	}
+	path := cache.diskPath(key)
+	tmp, err := os.CreateTemp(cache.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = fmt.Fprintf(tmp, "%s%d\n", diskHeader, ttl)
+	if err == nil {
+		_, err = tmp.Write(val)
+	}
+	closeErr := tmp.Close()
+	if err != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// readDisk returns the disk entry for key, keeping its original age so it
+// still expires on schedule once promoted back into memory.
+func (cache *Cache) readDisk(key string) (entry cacheEntry, available bool) {
+	if cache.dir == "" {
+		return cacheEntry{}, false
+	}
+	path := cache.diskPath(key)
+	entry, err := cache.readDiskEntry(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	if entry.stale() {
+		os.Remove(path)
+		return cacheEntry{}, false
+	}
+	entry.storedAt = time.Now()
+	return entry, true
+}
+
+// readDiskEntry reads the file at path, dated by its modification time.
+func (cache *Cache) readDiskEntry(path string) (entry cacheEntry, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	defer file.Close()
+	entry, reader, err := cache.readDiskHeader(file)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	entry.val, err = io.ReadAll(reader)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	return entry, nil
+}
+
+// readDiskHeader reads the age and TTL of the entry in file, leaving reader
+// at its value, so reaping need not read every value.
+func (cache *Cache) readDiskHeader(file *os.File) (entry cacheEntry, reader *bufio.Reader, err error) {
+	info, err := file.Stat()
+	if err != nil {
+		return cacheEntry{}, nil, err
+	}
+	entry = cacheEntry{createdAt: info.ModTime(), ttl: cache.diskDuration}
+	reader = bufio.NewReader(file)
+	if prefix, err := reader.Peek(len(diskHeader)); err != nil || string(prefix) != diskHeader {
+		return entry, reader, nil
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return cacheEntry{}, nil, fmt.Errorf("Error reading cache entry %s: %w", file.Name(), err)
+	}
+	ttl, err := strconv.ParseInt(strings.TrimSuffix(line[len(diskHeader):], "\n"), 10, 64)
+	if err != nil {
+		return cacheEntry{}, nil, fmt.Errorf("Error reading cache entry %s: %w", file.Name(), err)
+	}
+	entry.ttl = time.Duration(ttl)
+	return entry, reader, nil
+}
+
+func (cache *Cache) reapLoop(ctx context.Context, duration time.Duration) {
 	ticker := time.NewTicker(duration)
 	defer ticker.Stop()
 	for {
-		<-ticker.C
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 		cache.mu.Lock()
-		for k, v := range cache.CacheData {
-			if time.Since(v.createdAt) > duration {
-				delete(cache.CacheData, k)
+		for k, v := range cache.entries {
+			if cache.expired(v) {
+				cache.remove(k)
+				cache.stats.Expirations++
 			}
 		}
 		cache.mu.Unlock()
+		cache.reapDisk()
+	}
+}
+
+func (cache *Cache) reapDisk() {
+	if cache.dir == "" {
+		return
+	}
+	entries, err := os.ReadDir(cache.dir)
+	if err != nil {
+		return
+	}
+	for _, file := range entries {
+		if file.IsDir() {
+			continue
+		}
+		path := filepath.Join(cache.dir, file.Name())
+		if cache.diskEntryStale(path) {
+			os.Remove(path)
+		}
+	}
+}
+
+// diskEntryStale reports whether the file at path has outlived its TTL,
+// reading only its header.
+func (cache *Cache) diskEntryStale(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
 	}
+	defer file.Close()
+	entry, _, err := cache.readDiskHeader(file)
+	return err == nil && entry.stale()
 }
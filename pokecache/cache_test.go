@@ -1,8 +1,10 @@
 package pokecache
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
+	"os"
 	"testing"
 	"time"
 )
@@ -15,14 +17,236 @@ func randomBytes(n int) []byte {
 
 func TestCacheFunctioning(t *testing.T) {
 	cache := NewCache(2 * time.Second)
+	defer cache.Close()
 	for i := 0; i < 10; i++ {
 		cache.Add(fmt.Sprintf("%v index", i), randomBytes(25))
 	}
-	if len(cache.CacheData) != 10 {
+	if cache.Stats().Entries != 10 {
 		t.Errorf("Cache data not stored")
 	}
 	time.Sleep(3 * time.Second)
-	if len(cache.CacheData) != 0 {
+	if cache.Stats().Entries != 0 {
 		t.Errorf("Cache data not clearning up on schedule.")
 	}
 }
+
+func TestDiskCacheSurvivesNewCache(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewDiskCache(time.Minute, time.Minute, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	val := randomBytes(25)
+	first.Add("https://example.com/pokemon/pikachu", val)
+
+	second, err := NewDiskCache(time.Minute, time.Minute, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	data, available := second.Get("https://example.com/pokemon/pikachu")
+	if !available {
+		t.Fatalf("Expected disk entry to be found by a fresh cache")
+	}
+	if string(data) != string(val) {
+		t.Errorf("Disk entry does not match what was added")
+	}
+	if _, available := second.Get("https://example.com/pokemon/raichu"); available {
+		t.Errorf("Unexpected hit for a key that was never added")
+	}
+}
+
+func TestDiskCacheHonorsDuration(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewDiskCache(time.Second, time.Second, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	first.Add("stale", randomBytes(25))
+	time.Sleep(1500 * time.Millisecond)
+
+	second, err := NewDiskCache(time.Second, time.Second, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	if _, available := second.Get("stale"); available {
+		t.Errorf("Expected expired disk entry to be ignored")
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	cache.SetLimits(2, 0)
+	cache.Add("a", randomBytes(10))
+	cache.Add("b", randomBytes(10))
+	cache.Get("a")
+	cache.Add("c", randomBytes(10))
+
+	if _, available := cache.Get("b"); available {
+		t.Errorf("b should have been evicted as the least recently used")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, available := cache.Get(key); !available {
+			t.Errorf("%s should still be cached", key)
+		}
+	}
+	stats := cache.Stats()
+	if stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("got %d evictions and %d entries, want 1 and 2", stats.Evictions, stats.Entries)
+	}
+	if stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("got %d hits and %d misses, want 3 and 1", stats.Hits, stats.Misses)
+	}
+}
+
+func TestCacheEvictsToMaxBytes(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	cache.SetLimits(0, 25)
+	for i := 0; i < 5; i++ {
+		cache.Add(fmt.Sprintf("%v index", i), randomBytes(10))
+	}
+	stats := cache.Stats()
+	if stats.Bytes != 20 || stats.Entries != 2 || stats.Evictions != 3 {
+		t.Errorf("got %d bytes in %d entries after %d evictions, want 20 in 2 after 3", stats.Bytes, stats.Entries, stats.Evictions)
+	}
+	cache.Add("large", randomBytes(50))
+	if _, available := cache.Get("large"); !available {
+		t.Errorf("the newest entry should be kept even when it alone exceeds the limit")
+	}
+}
+
+func TestEvictedEntriesStayOnDisk(t *testing.T) {
+	cache, err := NewDiskCache(time.Minute, time.Minute, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	cache.SetLimits(1, 0)
+	val := randomBytes(25)
+	cache.Add("a", val)
+	cache.Add("b", randomBytes(25))
+	if stats := cache.Stats(); stats.Entries != 1 || stats.Evictions != 1 {
+		t.Fatalf("a should have been evicted from memory")
+	}
+	got, available := cache.Get("a")
+	if !available || string(got) != string(val) {
+		t.Errorf("evicted entry should still be served from disk")
+	}
+}
+
+func TestAddWithTTL(t *testing.T) {
+	cache := NewCache(time.Minute)
+	defer cache.Close()
+	cache.AddWithTTL("page", randomBytes(10), 50*time.Millisecond)
+	cache.Add("record", randomBytes(10))
+	time.Sleep(100 * time.Millisecond)
+
+	if _, available := cache.Get("page"); available {
+		t.Errorf("page should have expired after its own TTL")
+	}
+	if _, available := cache.Get("record"); !available {
+		t.Errorf("record should still be cached for the cache's duration")
+	}
+	if stats := cache.Stats(); stats.Expirations != 1 {
+		t.Errorf("got %d expirations, want 1", stats.Expirations)
+	}
+}
+
+func TestDiskCacheKeepsTTL(t *testing.T) {
+	dir := t.TempDir()
+	first, err := NewDiskCache(time.Minute, time.Minute, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	val := randomBytes(25)
+	first.AddWithTTL("page", randomBytes(25), 50*time.Millisecond)
+	first.AddWithTTL("record", val, time.Hour)
+	time.Sleep(100 * time.Millisecond)
+
+	second, err := NewDiskCache(time.Minute, time.Minute, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	if _, available := second.Get("page"); available {
+		t.Errorf("Expected the page's TTL to survive on disk")
+	}
+	data, age, available := second.GetWithAge("record")
+	if !available || string(data) != string(val) {
+		t.Fatalf("Expected the record to be read back without its header")
+	}
+	if age < 100*time.Millisecond {
+		t.Errorf("got age %v, want the time since it was first added", age)
+	}
+}
+
+func TestCloseStopsReaping(t *testing.T) {
+	cache := NewCache(50 * time.Millisecond)
+	cache.Add("key", randomBytes(10))
+	cache.Close()
+	cache.Close()
+	time.Sleep(150 * time.Millisecond)
+	if cache.Stats().Entries != 1 {
+		t.Errorf("Expected no reaping after Close")
+	}
+	if _, available := cache.Get("key"); available {
+		t.Errorf("Expected expired entries to still be dropped on lookup")
+	}
+}
+
+func TestNewCacheContextStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cache := NewCacheContext(ctx, 50*time.Millisecond)
+	defer cache.Close()
+	cache.Add("key", randomBytes(10))
+	cancel()
+	time.Sleep(150 * time.Millisecond)
+	if cache.Stats().Entries != 1 {
+		t.Errorf("Expected no reaping after the context is done")
+	}
+}
+
+func TestDiskCacheKeepsMemoryDurationShort(t *testing.T) {
+	cache, err := NewDiskCache(50*time.Millisecond, time.Minute, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	val := randomBytes(25)
+	cache.Add("key", val)
+	time.Sleep(150 * time.Millisecond)
+
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("Expected the entry to leave memory after the memory duration")
+	}
+	data, available := cache.Get("key")
+	if !available || string(data) != string(val) {
+		t.Errorf("Expected the entry to be read back from disk")
+	}
+}
+
+func TestReapDiskRemovesStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskCache(time.Minute, time.Minute, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	cache.AddWithTTL("page", randomBytes(25), 50*time.Millisecond)
+	cache.AddWithTTL("record", randomBytes(25), time.Hour)
+	time.Sleep(100 * time.Millisecond)
+
+	cache.reapDisk()
+	if _, err := os.Stat(cache.diskPath("page")); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale page to be removed from disk, got %v", err)
+	}
+	if _, err := os.Stat(cache.diskPath("record")); err != nil {
+		t.Errorf("Expected the record to stay on disk, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package pokecache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// TypedCache keeps decoded values in memory, so a hit skips decoding the
+// bytes a Cache would return. Values are shared between callers and must not
+// be modified. Expired entries are dropped on lookup, so unlike Cache it has
+// no reap loop to close.
+type TypedCache[K comparable, V any] struct {
+	entries    map[K]typedEntry[K, V]
+	mu         sync.Mutex
+	duration   time.Duration
+	recency    *list.List
+	maxEntries int
+	stats      Stats
+}
+
+type typedEntry[K comparable, V any] struct {
+	createdAt time.Time
+	ttl       time.Duration
+	val       V
+	element   *list.Element
+}
+
+// NewTypedCache returns a TypedCache whose entries expire after duration
+// unless added with their own TTL, holding at most maxEntries of them, or
+// any number when maxEntries is zero.
+func NewTypedCache[K comparable, V any](duration time.Duration, maxEntries int) *TypedCache[K, V] {
+	return &TypedCache[K, V]{entries: map[K]typedEntry[K, V]{}, duration: duration, recency: list.New(), maxEntries: maxEntries}
+}
+
+// Add stores val under key for the cache's duration.
+func (cache *TypedCache[K, V]) Add(key K, val V) {
+	cache.AddWithTTL(key, val, 0)
+}
+
+// AddWithTTL stores val under key until ttl has passed. A zero ttl means the
+// cache's duration.
+func (cache *TypedCache[K, V]) AddWithTTL(key K, val V, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = cache.duration
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.remove(key)
+	cache.entries[key] = typedEntry[K, V]{createdAt: time.Now(), ttl: ttl, val: val, element: cache.recency.PushFront(key)}
+	for cache.maxEntries > 0 && len(cache.entries) > cache.maxEntries {
+		cache.remove(cache.recency.Back().Value.(K))
+		cache.stats.Evictions++
+	}
+}
+
+func (cache *TypedCache[K, V]) Get(key K) (val V, available bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	entry, available := cache.entries[key]
+	if available && time.Since(entry.createdAt) > entry.ttl {
+		cache.remove(key)
+		cache.stats.Expirations++
+		available = false
+	}
+	if !available {
+		cache.stats.Misses++
+		return val, false
+	}
+	cache.stats.Hits++
+	cache.recency.MoveToFront(entry.element)
+	return entry.val, true
+}
+
+// Stats returns the usage counters. Bytes are not tracked for decoded values.
+func (cache *TypedCache[K, V]) Stats() Stats {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	stats := cache.stats
+	stats.Entries = len(cache.entries)
+	stats.MaxEntries = cache.maxEntries
+	return stats
+}
+
+// remove must be called with cache.mu held.
+func (cache *TypedCache[K, V]) remove(key K) {
+	entry, exists := cache.entries[key]
+	if !exists {
+		return
+	}
+	cache.recency.Remove(entry.element)
+	delete(cache.entries, key)
+}
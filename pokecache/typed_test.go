@@ -0,0 +1,48 @@
+package pokecache
+
+import (
+	"testing"
+	"time"
+)
+
+type decoded struct {
+	Name  string
+	Moves []string
+}
+
+func TestTypedCacheReturnsStoredValue(t *testing.T) {
+	cache := NewTypedCache[string, decoded](time.Minute, 0)
+	cache.Add("pikachu", decoded{Name: "pikachu", Moves: []string{"thunder-shock"}})
+
+	got, available := cache.Get("pikachu")
+	if !available || got.Name != "pikachu" || len(got.Moves) != 1 {
+		t.Errorf("got %+v, %v, want the stored pikachu", got, available)
+	}
+	if _, available := cache.Get("raichu"); available {
+		t.Errorf("Unexpected hit for a key that was never added")
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("got %d hits and %d misses, want 1 and 1", stats.Hits, stats.Misses)
+	}
+}
+
+func TestTypedCacheExpiresAndEvicts(t *testing.T) {
+	cache := NewTypedCache[int, string](time.Minute, 2)
+	cache.AddWithTTL(1, "page", 50*time.Millisecond)
+	cache.Add(2, "record")
+	time.Sleep(100 * time.Millisecond)
+	if _, available := cache.Get(1); available {
+		t.Errorf("Expected 1 to expire after its own TTL")
+	}
+
+	cache.Add(3, "record")
+	cache.Get(2)
+	cache.Add(4, "record")
+	if _, available := cache.Get(3); available {
+		t.Errorf("Expected 3 to be evicted as the least recently used")
+	}
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 || stats.Expirations != 1 {
+		t.Errorf("got %d entries, %d evictions and %d expirations, want 2, 1 and 1", stats.Entries, stats.Evictions, stats.Expirations)
+	}
+}
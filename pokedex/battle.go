@@ -0,0 +1,142 @@
+package pokedex
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	battle "github.com/anantashahane/pokedex/battle"
+)
+
+// maxMoveLookups bounds how many /move/ requests are spent finding damaging
+// moves for one combatant.
+const maxMoveLookups = 10
+
+func (client *Client) FetchMove(ctx context.Context, name string) (move MoveInfo, err error) {
+	move, err = fetchDecoded(ctx, client, client.decoded.moves, client.endpoint("move/"+name))
+	if err != nil {
+		return move, notFoundAs(ErrUnknownMove, name, err)
+	}
+	return move, nil
+}
+
+// BattleResult is a battle.Result along with what the trainer's Pokémon
+// gained from it. Experience is only awarded for a win.
+type BattleResult struct {
+	battle.Result
+	Experience int `json:"experience"`
+	LevelsUp   int `json:"levels_up"`
+	Level      int `json:"level"`
+}
+
+// Battle fights the caught Pokémon mine refers to against a wild Pokémon fetched by name
+// at the same level. Both Pokémon's types are loaded into the client's
+// TypeChart first, so an engine built on it sees the full damage relations.
+func (client *Client) Battle(ctx context.Context, engine *battle.Engine, mine, wild string) (result BattleResult, err error) {
+	own, err := Inspect(mine)
+	if err != nil {
+		return result, err
+	}
+	opponent, err := client.fetchPokemon(ctx, wild)
+	if err != nil {
+		return result, notFoundAs(ErrUnknownPokemon, wild, err)
+	}
+	first, err := client.Combatant(ctx, own.Pokemon, own.Level)
+	if err != nil {
+		return result, err
+	}
+	first.Name = own.Name()
+	first.Stats = own.battleStats()
+	second, err := client.Combatant(ctx, opponent, own.Level)
+	if err != nil {
+		return result, err
+	}
+	if second.Name == first.Name {
+		second.Name = "wild " + second.Name
+	}
+	err = client.TypeChart().Load(ctx, append(slices.Clone(first.Types), second.Types...)...)
+	if err != nil {
+		return result, err
+	}
+	result = BattleResult{Result: engine.Run(first, second), Level: own.Level}
+	if result.Winner == first.Name {
+		result.Experience = battleExperience(opponent.BaseExperience, second.Level)
+		result.LevelsUp = own.gainExperience(result.Experience)
+		result.Level = own.Level
+		own.gainEffort(opponent)
+		caughtPokemon[own.ID] = own
+	}
+	return result, nil
+}
+
+// Combatant converts pokemon into a battle.Combatant, giving it up to four
+// damaging moves from those it would most recently have learned by level up.
+func (client *Client) Combatant(ctx context.Context, pokemon Pokemon, level int) (combatant battle.Combatant, err error) {
+	combatant = battle.Combatant{Name: pokemon.Name, Level: level, Types: []string{}, Moves: []battle.Move{}}
+	for _, poketype := range pokemon.Types {
+		combatant.Types = append(combatant.Types, poketype.Type.Name)
+	}
+	for _, stat := range pokemon.Stats {
+		switch stat.Stat.Name {
+		case "hp":
+			combatant.Stats.HP = stat.BaseStat
+		case "attack":
+			combatant.Stats.Attack = stat.BaseStat
+		case "defense":
+			combatant.Stats.Defense = stat.BaseStat
+		case "special-attack":
+			combatant.Stats.SpecialAttack = stat.BaseStat
+		case "special-defense":
+			combatant.Stats.SpecialDefense = stat.BaseStat
+		case "speed":
+			combatant.Stats.Speed = stat.BaseStat
+		}
+	}
+	for i, name := range levelUpMoves(pokemon, level) {
+		if i >= maxMoveLookups || len(combatant.Moves) == 4 {
+			break
+		}
+		move, err := client.FetchMove(ctx, name)
+		if err != nil {
+			return combatant, err
+		}
+		if move.Power == 0 {
+			continue
+		}
+		combatant.Moves = append(combatant.Moves, battle.Move{
+			Name:        move.Name,
+			Type:        move.Type.Name,
+			DamageClass: move.DamageClass.Name,
+			Power:       move.Power,
+			Accuracy:    move.Accuracy,
+		})
+	}
+	return combatant, nil
+}
+
+// levelUpMoves returns the moves pokemon learns by level up at or below level,
+// most recently learned first.
+func levelUpMoves(pokemon Pokemon, level int) (names []string) {
+	learnedAt := map[string]int{}
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name != "level-up" || detail.LevelLearnedAt > level {
+				continue
+			}
+			if current, exists := learnedAt[move.Move.Name]; !exists || detail.LevelLearnedAt > current {
+				learnedAt[move.Move.Name] = detail.LevelLearnedAt
+			}
+		}
+	}
+	names = []string{}
+	for name := range learnedAt {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if learnedAt[a] != learnedAt[b] {
+			return learnedAt[b] - learnedAt[a]
+		}
+		return cmp.Compare(a, b)
+	})
+	return names
+}
@@ -0,0 +1,30 @@
+package pokedex
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCombatantUsesDamagingLevelUpMoves(t *testing.T) {
+	client, hits := newTestClient(t)
+	pokemon, err := client.fetchPokemon(context.Background(), "tentacool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	combatant, err := client.Combatant(context.Background(), pokemon, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if combatant.Stats.HP != 40 || combatant.Stats.Speed != 70 {
+		t.Errorf("Unexpected stats %+v", combatant.Stats)
+	}
+	if len(combatant.Types) != 2 || combatant.Types[0] != "water" {
+		t.Errorf("Unexpected types %v", combatant.Types)
+	}
+	if len(combatant.Moves) != 1 || combatant.Moves[0].Name != "poison-sting" {
+		t.Fatalf("Expected only poison-sting, got %+v", combatant.Moves)
+	}
+	if hits["/api/v2/move/hydro-pump"] != 0 || hits["/api/v2/move/surf"] != 0 {
+		t.Errorf("Fetched moves that are not learned by level up at level 50")
+	}
+}
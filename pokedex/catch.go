@@ -0,0 +1,166 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+var (
+	ErrUnknownBall   = errors.New("unknown ball")
+	ErrUnknownStatus = errors.New("unknown status")
+)
+
+// DefaultBall is thrown when CatchOptions does not name one.
+const DefaultBall = "pokeball"
+
+// BallModifiers holds the catch rate multiplier of each supported ball.
+var BallModifiers = map[string]float64{
+	"pokeball":   1,
+	"greatball":  1.5,
+	"ultraball":  2,
+	"masterball": 255,
+}
+
+// StatusModifiers holds the catch rate multiplier of each status condition.
+var StatusModifiers = map[string]float64{
+	"":          1,
+	"sleep":     2.5,
+	"freeze":    2.5,
+	"paralysis": 1.5,
+	"poison":    1.5,
+	"burn":      1.5,
+}
+
+// Random is the subset of *rand.Rand used for catching.
+type Random interface {
+	IntN(n int) int
+}
+
+type globalRandom struct{}
+
+func (globalRandom) IntN(n int) int {
+	return rand.IntN(n)
+}
+
+// SetRandom replaces the random source used by CatchPokemon, e.g. with a
+// seeded *rand.Rand for reproducible results.
+func (client *Client) SetRandom(random Random) {
+	client.random = random
+}
+
+type CatchOptions struct {
+	Ball   string
+	Status string
+	// HPPercent is the wild Pokémon's remaining health; zero means full health.
+	HPPercent int
+	// Area, when set, restricts the catch to Pokémon that can be encountered
+	// there and rolls whether the Pokémon shows up at all.
+	Area string
+}
+
+type CatchResult struct {
+	Pokemon string `json:"pokemon"`
+	Ball    string `json:"ball"`
+	// Shakes counts the shake checks passed, up to four.
+	Shakes int  `json:"shakes"`
+	Caught bool `json:"caught"`
+	// Chance is the probability this throw had of succeeding.
+	Chance float64 `json:"chance"`
+	// Level is the level the Pokémon was encountered at. Without an Area it is
+	// DefaultCatchLevel once caught and zero otherwise.
+	Level int `json:"level"`
+	// ID, Nature and Shiny describe the caught individual and are unset on an escape.
+	ID     int    `json:"id,omitempty"`
+	Nature string `json:"nature,omitempty"`
+	Shiny  bool   `json:"shiny"`
+	// Box is where the caught Pokémon was sent, with 0 meaning the party.
+	Box int `json:"box"`
+}
+
+// CatchPokemon throws a ball at the named Pokémon using the generation III/IV
+// capture formula with the species' capture_rate.
+func (client *Client) CatchPokemon(ctx context.Context, name string, options CatchOptions) (result CatchResult, err error) {
+	if storageFull() {
+		return result, ErrStorageFull
+	}
+	if options.Ball == "" {
+		options.Ball = DefaultBall
+	}
+	ball, exists := BallModifiers[options.Ball]
+	if !exists {
+		return result, fmt.Errorf("%w %q", ErrUnknownBall, options.Ball)
+	}
+	status, exists := StatusModifiers[options.Status]
+	if !exists {
+		return result, fmt.Errorf("%w %q", ErrUnknownStatus, options.Status)
+	}
+	hpPercent := options.HPPercent
+	if hpPercent <= 0 || hpPercent > 100 {
+		hpPercent = 100
+	}
+
+	level := 0
+	if options.Area != "" {
+		level, err = client.encounter(ctx, options.Area, name)
+		if err != nil {
+			return result, err
+		}
+	}
+	pokemon, err := client.fetchPokemon(ctx, name)
+	if err != nil {
+		return result, notFoundAs(ErrUnknownPokemon, name, err)
+	}
+	species, err := client.fetchSpecies(ctx, pokemon.Species.Name)
+	if err != nil {
+		return result, err
+	}
+
+	result = CatchResult{Pokemon: pokemon.Name, Ball: options.Ball, Level: level}
+	rate := modifiedCatchRate(species.CaptureRate, hpPercent, ball, status)
+	threshold := shakeThreshold(rate)
+	result.Chance = math.Min(math.Pow(float64(threshold)/65536, 4), 1)
+	result.Shakes = shakeChecks(client.random, threshold)
+	result.Caught = result.Shakes == 4
+	if result.Caught {
+		caught, box, err := addCaught(newCaughtPokemon(client.random, pokemon, species, level, options.Area))
+		if err != nil {
+			return result, err
+		}
+		result.ID = caught.ID
+		result.Box = box
+		result.Level = caught.Level
+		result.Nature = caught.Nature
+		result.Shiny = caught.Shiny
+	}
+	return result, nil
+}
+
+// modifiedCatchRate is the "a" value of the capture formula, where hpPercent
+// stands in for the ratio of current to maximum HP.
+func modifiedCatchRate(captureRate, hpPercent int, ball, status float64) float64 {
+	hpFactor := float64(300-2*hpPercent) / 300
+	return math.Floor(hpFactor*float64(captureRate)*ball) * status
+}
+
+// shakeThreshold is the "b" value each shake check compares against. A rate of
+// 255 or more is a guaranteed catch.
+func shakeThreshold(rate float64) int {
+	if rate >= 255 {
+		return 65536
+	}
+	if rate <= 0 {
+		return 0
+	}
+	return int(1048560 / math.Floor(math.Sqrt(math.Floor(math.Sqrt(math.Floor(16711680/rate))))))
+}
+
+// shakeChecks stops at the first failed check, as the ball breaks open there.
+func shakeChecks(random Random, threshold int) (shakes int) {
+	for shakes < 4 && random.IntN(65536) < threshold {
+		shakes++
+	}
+	return shakes
+}
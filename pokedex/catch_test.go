@@ -0,0 +1,89 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// sequenceRandom returns values in order, repeating the last one.
+type sequenceRandom struct {
+	values []int
+}
+
+func (r *sequenceRandom) IntN(n int) int {
+	value := r.values[0]
+	if len(r.values) > 1 {
+		r.values = r.values[1:]
+	}
+	return min(value, n-1)
+}
+
+func TestShakeThreshold(t *testing.T) {
+	cases := []struct {
+		captureRate int
+		hpPercent   int
+		ball        float64
+		status      float64
+		expected    int
+	}{
+		// Pikachu at full health with a Poké Ball.
+		{captureRate: 190, hpPercent: 100, ball: 1, status: 1, expected: 47661},
+		// Mewtwo at full health with an Ultra Ball.
+		{captureRate: 3, hpPercent: 100, ball: 2, status: 1, expected: 19784},
+		// A weakened, sleeping Caterpie is guaranteed.
+		{captureRate: 255, hpPercent: 1, ball: 1, status: 2.5, expected: 65536},
+	}
+	for _, c := range cases {
+		rate := modifiedCatchRate(c.captureRate, c.hpPercent, c.ball, c.status)
+		actual := shakeThreshold(rate)
+		if actual != c.expected {
+			t.Errorf("Expected threshold %v for %+v, got %v", c.expected, c, actual)
+		}
+	}
+}
+
+func TestCatchPokemonReportsShakes(t *testing.T) {
+	client, _ := newTestClient(t)
+	resetCollection(t)
+
+	client.SetRandom(&sequenceRandom{values: []int{0, 0, 65535}})
+	result, err := client.CatchPokemon(context.Background(), "tentacool", CatchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Caught || result.Shakes != 2 || result.Ball != DefaultBall {
+		t.Errorf("Expected an escape after 2 shakes, got %+v", result)
+	}
+	if _, err := Inspect("tentacool"); err == nil {
+		t.Errorf("Escaped pokemon was added to the pokedex")
+	}
+
+	client.SetRandom(&sequenceRandom{values: []int{0}})
+	result, err = client.CatchPokemon(context.Background(), "tentacool", CatchOptions{Ball: "ultraball"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Caught || result.Shakes != 4 {
+		t.Errorf("Expected a catch, got %+v", result)
+	}
+	if math.Abs(result.Chance-0.503) > 0.01 {
+		t.Errorf("Unexpected catch chance %v", result.Chance)
+	}
+	caught, err := Inspect("tentacool")
+	if err != nil {
+		t.Fatalf("Expected tentacool in the pokedex: %v", err)
+	}
+	if caught.ID != result.ID || caught.Level != DefaultCatchLevel || !caught.Shiny || caught.Nature != "hardy" {
+		t.Errorf("Unexpected caught individual %+v", caught)
+	}
+}
+
+func TestCatchPokemonRejectsUnknownBall(t *testing.T) {
+	client, _ := newTestClient(t)
+	_, err := client.CatchPokemon(context.Background(), "tentacool", CatchOptions{Ball: "safariball"})
+	if !errors.Is(err, ErrUnknownBall) {
+		t.Errorf("Expected ErrUnknownBall, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package pokedex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pokecache "github.com/anantashahane/pokedex/pokecache"
+)
+
+const DefaultBaseURL = "https://pokeapi.co/api/v2"
+
+// DefaultTimeout bounds a single request attempt, not the whole retry sequence.
+const DefaultTimeout = 10 * time.Second
+
+// RetryPolicy controls how transport failures, 5xx and 429 responses are
+// retried. The delay doubles after every attempt, capped at MaxDelay.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// Paginated list pages shift as the API gains entries, so they are cached
+// briefly, while individual records such as /pokemon/{name} effectively never
+// change and are kept much longer.
+const (
+	ListCacheTTL   = 10 * time.Minute
+	RecordCacheTTL = 30 * 24 * time.Hour
+)
+
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, BaseDelay: 250 * time.Millisecond, MaxDelay: 4 * time.Second}
+
+// Client talks to a PokeAPI compatible server, caching every response body.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	cache      *pokecache.Cache
+	decoded    decodedCaches
+	flights    flightGroup
+	timeout    time.Duration
+	retry      RetryPolicy
+	random     Random
+
+	// ownsCache is set when NewClient made the cache, so Close stops it.
+	ownsCache bool
+
+	typeChartOnce sync.Once
+	typeChart     *TypeChart
+}
+
+// NewClient returns a Client for baseURL. A nil httpClient or cache falls back
+// to http.DefaultClient and a two minute in-memory cache respectively; only
+// the latter needs Close.
+func NewClient(baseURL string, httpClient *http.Client, cache *pokecache.Cache) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	ownsCache := cache == nil
+	if ownsCache {
+		cache = pokecache.NewCache(time.Minute * 2)
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		cache:      cache,
+		ownsCache:  ownsCache,
+		decoded:    newDecodedCaches(cache.MemoryDuration()),
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy,
+		random:     globalRandom{},
+	}
+}
+
+// Close stops the cache NewClient made when given none. A cache passed to
+// NewClient belongs to the caller, who closes it.
+func (client *Client) Close() {
+	if client.ownsCache {
+		client.cache.Close()
+	}
+}
+
+// SetTimeout changes the per-attempt timeout. Zero disables it.
+func (client *Client) SetTimeout(timeout time.Duration) {
+	client.timeout = timeout
+}
+
+func (client *Client) SetRetryPolicy(policy RetryPolicy) {
+	client.retry = policy
+}
+
+// CacheStats reports how the response cache has been used this session.
+func (client *Client) CacheStats() pokecache.Stats {
+	return client.cache.Stats()
+}
+
+// DecodedCacheStats reports how the caches of decoded responses have been
+// used this session. A hit there never reaches the response cache.
+func (client *Client) DecodedCacheStats() pokecache.Stats {
+	return client.decoded.stats()
+}
+
+func (client *Client) endpoint(path string) string {
+	return client.baseURL + "/" + path
+}
+
+// resolve rewrites a resource URL returned by the API, which always points at
+// the public PokeAPI, onto this client's base URL.
+func (client *Client) resolve(url string) string {
+	_, path, found := strings.Cut(url, "/api/v2/")
+	if !found {
+		return url
+	}
+	return client.endpoint(path)
+}
+
+func (client *Client) fetchData(ctx context.Context, url string) (body []byte, err error) {
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		body, retryAfter, err = client.fetchOnce(ctx, url)
+		if err == nil || !retryable(err) || attempt >= client.retry.MaxRetries || ctx.Err() != nil {
+			return body, err
+		}
+		delay := max(client.retry.backoff(attempt), retryAfter)
+		if client.retry.MaxDelay > 0 && delay > client.retry.MaxDelay {
+			delay = client.retry.MaxDelay
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return []byte{}, &TransportError{URL: url, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff is BaseDelay doubled attempt times, stopping at MaxDelay, or
+// before overflowing when there is none.
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.BaseDelay
+	for range attempt {
+		if (policy.MaxDelay > 0 && delay >= policy.MaxDelay) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		return policy.MaxDelay
+	}
+	return delay
+}
+
+func (client *Client) fetchOnce(ctx context.Context, url string) (body []byte, retryAfter time.Duration, err error) {
+	if client.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return []byte{}, 0, fmt.Errorf("Error building request for %s: %w", url, err)
+	}
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return []byte{}, 0, &TransportError{URL: url, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return []byte{}, parseRetryAfter(resp.Header.Get("Retry-After")), &StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []byte{}, 0, &TransportError{URL: url, Err: err}
+	}
+	return data, 0, nil
+}
+
+func retryable(err error) bool {
+	switch e := err.(type) {
+	case *TransportError:
+		return true
+	case *StatusError:
+		return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// parseRetryAfter only understands the delay-seconds form of the header.
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// fetchCached serves url from the cache, only going to the network on a miss.
+// Concurrent misses for the same url share one request.
+func (client *Client) fetchCached(ctx context.Context, url string) (body []byte, err error) {
+	data, available := client.cache.Get(url)
+	if available {
+		return data, nil
+	}
+	fetch := func() ([]byte, error) {
+		// A flight for url may have filled the cache since the lookup above.
+		if data, available := client.cache.Get(url); available {
+			return data, nil
+		}
+		data, err := client.fetchData(ctx, url)
+		if err != nil {
+			return []byte{}, err
+		}
+		client.cache.AddWithTTL(url, data, cacheTTL(url))
+		return data, nil
+	}
+	for {
+		data, abandoned, err := client.flights.do(ctx, url, fetch)
+		// Another caller giving up must not fail this one, so take over its
+		// fetch. A fetch that failed on its own, e.g. by timing out, is not retried.
+		if abandoned && ctx.Err() == nil {
+			continue
+		}
+		return data, err
+	}
+}
+
+// decodedCacheEntries bounds each decoded cache, as decoded values are much
+// larger than their responses; the byte cache behind it still serves anything
+// evicted, only at the cost of decoding it again.
+const decodedCacheEntries = 32
+
+// decodedCaches hold the structs decoded from responses, keyed by URL, so
+// repeat lookups skip json.Unmarshal. They keep values no longer than the
+// byte cache keeps responses in memory.
+type decodedCaches struct {
+	locations      *pokecache.TypedCache[string, PokeLocations]
+	locationInfo   *pokecache.TypedCache[string, LocationInfo]
+	pokemon        *pokecache.TypedCache[string, Pokemon]
+	species        *pokecache.TypedCache[string, SpeciesInfo]
+	evolutionChain *pokecache.TypedCache[string, EvolutionChainInfo]
+	moves          *pokecache.TypedCache[string, MoveInfo]
+	abilities      *pokecache.TypedCache[string, AbilityInfo]
+	types          *pokecache.TypedCache[string, TypeInfo]
+}
+
+func newDecodedCaches(duration time.Duration) decodedCaches {
+	return decodedCaches{
+		locations:      pokecache.NewTypedCache[string, PokeLocations](duration, decodedCacheEntries),
+		locationInfo:   pokecache.NewTypedCache[string, LocationInfo](duration, decodedCacheEntries),
+		pokemon:        pokecache.NewTypedCache[string, Pokemon](duration, decodedCacheEntries),
+		species:        pokecache.NewTypedCache[string, SpeciesInfo](duration, decodedCacheEntries),
+		evolutionChain: pokecache.NewTypedCache[string, EvolutionChainInfo](duration, decodedCacheEntries),
+		moves:          pokecache.NewTypedCache[string, MoveInfo](duration, decodedCacheEntries),
+		abilities:      pokecache.NewTypedCache[string, AbilityInfo](duration, decodedCacheEntries),
+		types:          pokecache.NewTypedCache[string, TypeInfo](duration, decodedCacheEntries),
+	}
+}
+
+// stats adds up the counters of every decoded cache.
+func (decoded decodedCaches) stats() (total pokecache.Stats) {
+	for _, stats := range []pokecache.Stats{
+		decoded.locations.Stats(),
+		decoded.locationInfo.Stats(),
+		decoded.pokemon.Stats(),
+		decoded.species.Stats(),
+		decoded.evolutionChain.Stats(),
+		decoded.moves.Stats(),
+		decoded.abilities.Stats(),
+		decoded.types.Stats(),
+	} {
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Evictions += stats.Evictions
+		total.Expirations += stats.Expirations
+		total.Entries += stats.Entries
+		total.MaxEntries += stats.MaxEntries
+	}
+	return total
+}
+
+// fetchDecoded returns the value decoded from url, only fetching and decoding
+// it when decoded has no copy. Returned values are shared and must not be modified.
+func fetchDecoded[V any](ctx context.Context, client *Client, decoded *pokecache.TypedCache[string, V], url string) (value V, err error) {
+	if value, available := decoded.Get(url); available {
+		return value, nil
+	}
+	data, err := client.fetchCached(ctx, url)
+	if err != nil {
+		return value, err
+	}
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return value, fmt.Errorf("Error decoding data received from %s: %w", url, err)
+	}
+	decoded.AddWithTTL(url, value, min(cacheTTL(url), client.cache.MemoryDuration()))
+	return value, nil
+}
+
+// cacheTTL tells list pages, which carry a query, apart from records.
+func cacheTTL(url string) time.Duration {
+	if strings.Contains(url, "?") {
+		return ListCacheTTL
+	}
+	return RecordCacheTTL
+}
@@ -0,0 +1,481 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pokecache "github.com/anantashahane/pokedex/pokecache"
+)
+
+var fixtures = map[string]string{
+	"/api/v2/location-area/": `{
+		"count": 2,
+		"next": "",
+		"previous": "",
+		"results": [
+			{"name": "canalave-city-area", "url": ""},
+			{"name": "eterna-city-area", "url": ""}
+		]
+	}`,
+	"/api/v2/location-area/canalave-city-area": `{
+		"name": "canalave-city-area",
+		"names": [{"name": "Canalave City", "language": {"name": "en"}}],
+		"pokemon_encounters": [
+			{"pokemon": {"name": "tentacool", "url": ""}, "version_details": [
+				{"max_chance": 60, "encounter_details": [
+					{"chance": 60, "min_level": 20, "max_level": 30}
+				]},
+				{"max_chance": 35, "encounter_details": [
+					{"chance": 35, "min_level": 15, "max_level": 20}
+				]}
+			]},
+			{"pokemon": {"name": "staryu", "url": ""}}
+		]
+	}`,
+	"/api/v2/pokemon/tentacool": `{
+		"name": "tentacool",
+		"base_experience": -1,
+		"species": {"name": "tentacool"},
+		"height": 9,
+		"weight": 455,
+		"stats": [
+			{"base_stat": 40, "stat": {"name": "hp"}},
+			{"base_stat": 70, "stat": {"name": "speed"}}
+		],
+		"types": [
+			{"slot": 1, "type": {"name": "water"}},
+			{"slot": 2, "type": {"name": "poison"}}
+		],
+		"moves": [
+			{"move": {"name": "poison-sting"}, "version_group_details": [
+				{"level_learned_at": 1, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "red-blue", "url": "https://pokeapi.co/api/v2/version-group/1/"}},
+				{"level_learned_at": 1, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "sword-shield", "url": "https://pokeapi.co/api/v2/version-group/20/"}}
+			]},
+			{"move": {"name": "supersonic"}, "version_group_details": [
+				{"level_learned_at": 8, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "sword-shield", "url": "https://pokeapi.co/api/v2/version-group/20/"}},
+				{"level_learned_at": 7, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "red-blue", "url": "https://pokeapi.co/api/v2/version-group/1/"}}
+			]},
+			{"move": {"name": "hydro-pump"}, "version_group_details": [
+				{"level_learned_at": 55, "move_learn_method": {"name": "level-up"}, "version_group": {"name": "sword-shield", "url": "https://pokeapi.co/api/v2/version-group/20/"}}
+			]},
+			{"move": {"name": "surf"}, "version_group_details": [
+				{"level_learned_at": 0, "move_learn_method": {"name": "machine"}, "version_group": {"name": "red-blue", "url": "https://pokeapi.co/api/v2/version-group/1/"}}
+			]}
+		]
+	}`,
+	"/api/v2/pokemon-species/tentacool": `{
+		"name": "tentacool",
+		"capture_rate": 190,
+		"names": [
+			{"name": "メノクラゲ", "language": {"name": "ja-Hrkt"}},
+			{"name": "Tentacool", "language": {"name": "de"}}
+		]
+	}`,
+	"/api/v2/pokemon-species/eevee": `{
+		"name": "eevee",
+		"id": 133,
+		"capture_rate": 45,
+		"evolution_chain": {"url": "https://pokeapi.co/api/v2/evolution-chain/67/"},
+		"flavor_text_entries": [
+			{"flavor_text": "Its genetic code is\nirregular.", "language": {"name": "en"}},
+			{"flavor_text": "Son code génétique est irrégulier.", "language": {"name": "fr"}}
+		],
+		"genera": [{"genus": "Evolution Pokémon", "language": {"name": "en"}}]
+	}`,
+	"/api/v2/pokemon-species/vaporeon": `{
+		"name": "vaporeon",
+		"evolution_chain": {"url": "https://pokeapi.co/api/v2/evolution-chain/67/"}
+	}`,
+	"/api/v2/evolution-chain/67/": `{
+		"id": 67,
+		"chain": {
+			"species": {"name": "eevee"},
+			"evolution_details": [],
+			"evolves_to": [
+				{
+					"species": {"name": "vaporeon"},
+					"evolution_details": [{"trigger": {"name": "use-item"}, "item": {"name": "water-stone"}, "min_level": null}],
+					"evolves_to": []
+				},
+				{
+					"species": {"name": "espeon"},
+					"evolution_details": [{"trigger": {"name": "level-up"}, "min_happiness": 160, "time_of_day": "day"}],
+					"evolves_to": []
+				}
+			]
+		}
+	}`,
+	"/api/v2/pokemon/vaporeon": `{
+		"name": "vaporeon",
+		"base_experience": 184,
+		"species": {"name": "vaporeon"}
+	}`,
+	"/api/v2/type/water": `{
+		"name": "water",
+		"names": [{"name": "Eau", "language": {"name": "fr"}}],
+		"damage_relations": {
+			"double_damage_from": [{"name": "electric"}, {"name": "grass"}],
+			"half_damage_from": [{"name": "fire"}, {"name": "water"}, {"name": "ice"}, {"name": "steel"}],
+			"no_damage_from": []
+		}
+	}`,
+	"/api/v2/type/poison": `{
+		"name": "poison",
+		"damage_relations": {
+			"double_damage_from": [{"name": "ground"}, {"name": "psychic"}],
+			"half_damage_from": [{"name": "fighting"}, {"name": "poison"}, {"name": "bug"}, {"name": "grass"}, {"name": "fairy"}],
+			"no_damage_from": []
+		}
+	}`,
+	"/api/v2/move/poison-sting": `{
+		"name": "poison-sting",
+		"accuracy": 100,
+		"power": 15,
+		"damage_class": {"name": "physical"},
+		"type": {"name": "poison"}
+	}`,
+	"/api/v2/move/thunder-shock": `{
+		"name": "thunder-shock",
+		"accuracy": 100,
+		"power": 40,
+		"pp": 30,
+		"effect_chance": 10,
+		"effect_entries": [
+			{"effect": "Inflicts regular damage.", "short_effect": "Has a $effect_chance% chance to\nparalyze the target.", "language": {"name": "en"}}
+		],
+		"learned_by_pokemon": [{"name": "pikachu"}, {"name": "raichu"}],
+		"damage_class": {"name": "special"},
+		"type": {"name": "electric"}
+	}`,
+	"/api/v2/ability/static": `{
+		"name": "static",
+		"effect_entries": [
+			{"effect": "Statik", "short_effect": "Kann bei Berührung paralysieren.", "language": {"name": "de"}}
+		],
+		"flavor_text_entries": [
+			{"flavor_text": "Contact may cause\nparalysis.", "language": {"name": "en"}, "version_group": {"name": "ruby-sapphire"}},
+			{"flavor_text": "The Pokémon is charged with static electricity.", "language": {"name": "en"}, "version_group": {"name": "sword-shield"}}
+		],
+		"pokemon": [
+			{"is_hidden": false, "pokemon": {"name": "pikachu"}, "slot": 1},
+			{"is_hidden": true, "pokemon": {"name": "electrike"}, "slot": 3}
+		]
+	}`,
+	"/api/v2/move/supersonic": `{
+		"name": "supersonic",
+		"accuracy": 55,
+		"power": null,
+		"damage_class": {"name": "status"},
+		"type": {"name": "normal"}
+	}`,
+}
+
+// newTestClient serves fixtures from an httptest.Server and counts requests per path.
+func newTestClient(t *testing.T) (client *Client, hits map[string]int) {
+	t.Helper()
+	hits = map[string]int{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		body, exists := fixtures[r.URL.Path]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(cache.Close)
+	return NewClient(server.URL+"/api/v2", server.Client(), cache), hits
+}
+
+func TestClientGetLocations(t *testing.T) {
+	client, _ := newTestClient(t)
+	page, err := client.GetLocations(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locations := page.Locations
+	expected := []string{"canalave-city-area", "eterna-city-area"}
+	if len(locations) != len(expected) {
+		t.Fatalf("Expected %v locations, got %v", len(expected), len(locations))
+	}
+	for i := range expected {
+		if locations[i] != expected[i] {
+			t.Errorf("Expected location %s, got %s", expected[i], locations[i])
+		}
+	}
+}
+
+func TestClientGetPokemonsIsCached(t *testing.T) {
+	client, hits := newTestClient(t)
+	for i := 0; i < 2; i++ {
+		pokemons, err := client.GetPokemons(context.Background(), "canalave-city-area")
+		if err != nil || len(pokemons) != 2 {
+			t.Fatalf("Expected 2 pokemons, got %v", pokemons)
+		}
+	}
+	if hits["/api/v2/location-area/canalave-city-area"] != 1 {
+		t.Errorf("Expected a single request, got %v", hits["/api/v2/location-area/canalave-city-area"])
+	}
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(fixtures["/api/v2/pokemon/tentacool"]))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, server.Client(), nil)
+	defer client.Close()
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond})
+
+	pokemon, err := client.fetchPokemon(context.Background(), "tentacool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pokemon.Name != "tentacool" || attempts != 3 {
+		t.Errorf("Expected tentacool after 3 attempts, got %q after %v", pokemon.Name, attempts)
+	}
+}
+
+func TestClientNotFoundIsNotRetried(t *testing.T) {
+	client, hits := newTestClient(t)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond})
+
+	_, err := client.fetchPokemon(context.Background(), "missingno")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+	if hits["/api/v2/pokemon/missingno"] != 1 {
+		t.Errorf("Expected a single request, got %v", hits["/api/v2/pokemon/missingno"])
+	}
+}
+
+func TestClientCancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, server.Client(), nil)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.fetchPokemon(ctx, "tentacool")
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a TransportError wrapping the deadline, got %v", err)
+	}
+}
+
+func TestClientGetPokemonsUnknownArea(t *testing.T) {
+	client, _ := newTestClient(t)
+	_, err := client.GetPokemons(context.Background(), "nowhere")
+	if !errors.Is(err, ErrUnknownArea) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrUnknownArea, got %v", err)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	if got := cacheTTL("https://pokeapi.co/api/v2/location-area/?offset=20&limit=20"); got != ListCacheTTL {
+		t.Errorf("list page cached for %v, want %v", got, ListCacheTTL)
+	}
+	if got := cacheTTL("https://pokeapi.co/api/v2/pokemon/pikachu"); got != RecordCacheTTL {
+		t.Errorf("record cached for %v, want %v", got, RecordCacheTTL)
+	}
+}
+
+func TestFetchPokemonReusesDecodedValue(t *testing.T) {
+	client, hits := newTestClient(t)
+	for range 3 {
+		pokemon, err := client.fetchPokemon(context.Background(), "tentacool")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pokemon.Name != "tentacool" {
+			t.Fatalf("got %q, want tentacool", pokemon.Name)
+		}
+	}
+	if hits["/api/v2/pokemon/tentacool"] != 1 {
+		t.Errorf("got %d requests, want 1", hits["/api/v2/pokemon/tentacool"])
+	}
+	if stats := client.DecodedCacheStats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("got %d decoded hits and %d misses, want 2 and 1", stats.Hits, stats.Misses)
+	}
+}
+
+func TestDecodedValuesKeepMemoryDuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fixtures["/api/v2/pokemon/tentacool"]))
+	}))
+	t.Cleanup(server.Close)
+	cache := pokecache.NewCache(50 * time.Millisecond)
+	t.Cleanup(cache.Close)
+	client := NewClient(server.URL, server.Client(), cache)
+
+	for range 2 {
+		_, err := client.fetchPokemon(context.Background(), "tentacool")
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if stats := client.DecodedCacheStats(); stats.Expirations != 1 || stats.MaxEntries != 8*decodedCacheEntries {
+		t.Errorf("got %d expirations and room for %d entries, want 1 and %d", stats.Expirations, stats.MaxEntries, 8*decodedCacheEntries)
+	}
+}
+
+// newGatedServer serves tentacool once release is closed, counting requests.
+func newGatedServer(t *testing.T) (client *Client, requests *atomic.Int32, release chan struct{}) {
+	t.Helper()
+	requests = &atomic.Int32{}
+	release = make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-release:
+			w.Write([]byte(fixtures["/api/v2/pokemon/tentacool"]))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(cache.Close)
+	return NewClient(server.URL, server.Client(), cache), requests, release
+}
+
+func TestConcurrentFetchesShareOneRequest(t *testing.T) {
+	client, requests, release := newGatedServer(t)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.fetchPokemon(context.Background(), "tentacool")
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+}
+
+func TestCancelledCallerDoesNotFailOthers(t *testing.T) {
+	client, requests, release := newGatedServer(t)
+	client.SetRetryPolicy(RetryPolicy{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.fetchPokemon(ctx, "tentacool")
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	second := make(chan error, 1)
+	go func() {
+		_, err := client.fetchPokemon(context.Background(), "tentacool")
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancelled caller to fail with context.Canceled, got %v", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("Expected the other caller to take over the fetch, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+}
+
+func TestTimedOutFetchIsNotRepeatedByWaiters(t *testing.T) {
+	// release is never closed, so the server never responds.
+	client, requests, _ := newGatedServer(t)
+	client.SetTimeout(50 * time.Millisecond)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	start := time.Now()
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.fetchPokemon(context.Background(), "tentacool")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err == nil {
+			t.Errorf("Expected every caller to fail when the server never responds")
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2 from a single fetch and its retry", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected waiters to share the timed out fetch, took %v", elapsed)
+	}
+}
+
+func TestGetLocationsFollowsPagesOnBaseURL(t *testing.T) {
+	client, hits := newTestClient(t)
+	// The API's next and previous links always point at the public PokeAPI.
+	page, err := client.GetLocations(context.Background(), "https://pokeapi.co/api/v2/location-area/?offset=20&limit=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Locations) != 2 || hits["/api/v2/location-area/"] != 1 {
+		t.Errorf("Expected the next page to be fetched from the test server, got %v after %d requests", page.Locations, hits["/api/v2/location-area/"])
+	}
+}
+
+func TestRetryBackoffDoesNotOverflow(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 100, BaseDelay: 250 * time.Millisecond, MaxDelay: 4 * time.Second}
+	for _, attempt := range []int{0, 1, 4, 40, 63, 99} {
+		delay := policy.backoff(attempt)
+		if delay <= 0 || delay > policy.MaxDelay {
+			t.Errorf("attempt %d: got delay %v, want between 0 and %v", attempt, delay, policy.MaxDelay)
+		}
+	}
+	if got := policy.backoff(2); got != time.Second {
+		t.Errorf("got %v for the third attempt, want 1s", got)
+	}
+	uncapped := RetryPolicy{BaseDelay: time.Second}
+	if got := uncapped.backoff(99); got <= 0 {
+		t.Errorf("got %v without MaxDelay, want a positive delay", got)
+	}
+}
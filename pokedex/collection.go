@@ -0,0 +1,204 @@
+package pokedex
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrAmbiguous = errors.New("matches more than one caught pokemon")
+
+// DefaultCatchLevel is the level given to Pokémon caught without an encounter roll.
+const DefaultCatchLevel = 5
+
+const (
+	maxLevel     = 100
+	maxHappiness = 255
+	// levelUpHappiness is the friendship gained on each level up.
+	levelUpHappiness = 5
+	// shinyOdds is the one-in-N chance of a caught Pokémon being shiny.
+	shinyOdds = 4096
+)
+
+// caughtPokemon holds the collection by ID; nextID is the ID the next catch gets.
+var (
+	caughtPokemon = map[int]CaughtPokemon{}
+	nextID        = 1
+)
+
+// CaughtPokemon is one individual Pokémon in the trainer's collection along
+// with the state it has gained since being caught.
+type CaughtPokemon struct {
+	ID         int     `json:"id"`
+	Nickname   string  `json:"nickname,omitempty"`
+	Pokemon    Pokemon `json:"pokemon"`
+	Level      int     `json:"level"`
+	Experience int     `json:"experience"`
+	GrowthRate string  `json:"growth_rate"`
+	Happiness  int     `json:"happiness"`
+	HeldItem   string  `json:"held_item,omitempty"`
+	IVs        Stats   `json:"ivs"`
+	EVs        Stats   `json:"evs"`
+	Nature     string  `json:"nature"`
+	Shiny      bool    `json:"shiny"`
+	// CaughtAt and CaughtIn record when and in which area it was caught.
+	CaughtAt time.Time `json:"caught_at"`
+	CaughtIn string    `json:"caught_in,omitempty"`
+}
+
+// Name returns the nickname, or the Pokémon's name when it has none.
+func (caught CaughtPokemon) Name() string {
+	if caught.Nickname != "" {
+		return caught.Nickname
+	}
+	return caught.Pokemon.Name
+}
+
+// newCaughtPokemon rolls the individual values, nature and shininess of a
+// freshly caught pokemon. It is given an ID when added to the collection.
+func newCaughtPokemon(random Random, pokemon Pokemon, species SpeciesInfo, level int, area string) CaughtPokemon {
+	if level <= 0 {
+		level = DefaultCatchLevel
+	}
+	growthRate := species.GrowthRate.Name
+	caught := CaughtPokemon{
+		Pokemon:    pokemon,
+		Level:      level,
+		Experience: experienceForLevel(growthRate, level),
+		GrowthRate: growthRate,
+		Happiness:  species.BaseHappiness,
+		Nature:     Natures[random.IntN(len(Natures))].Name,
+		Shiny:      random.IntN(shinyOdds) == 0,
+		CaughtAt:   now(),
+		CaughtIn:   area,
+	}
+	for _, name := range StatNames {
+		caught.IVs.set(name, random.IntN(maxIV+1))
+	}
+	return caught
+}
+
+// addCaught gives caught the next free ID and stores it in the party, or the
+// first box with room, returning the box it went to.
+func addCaught(caught CaughtPokemon) (added CaughtPokemon, box int, err error) {
+	if storageFull() {
+		return caught, 0, ErrStorageFull
+	}
+	caught.ID = nextID
+	nextID++
+	caughtPokemon[caught.ID] = caught
+	box, err = place(caught.ID)
+	return caught, box, err
+}
+
+// findCaught resolves reference to a single caught Pokémon. A reference is an
+// ID, a nickname, or a Pokémon name that only one caught Pokémon has.
+func findCaught(reference string) (caught CaughtPokemon, err error) {
+	if id, err := strconv.Atoi(strings.TrimPrefix(reference, "#")); err == nil {
+		caught, exists := caughtPokemon[id]
+		if !exists {
+			return CaughtPokemon{}, ErrNotCaught
+		}
+		return caught, nil
+	}
+	matches := []CaughtPokemon{}
+	for _, candidate := range ViewPokedex() {
+		if strings.EqualFold(candidate.Nickname, reference) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		for _, candidate := range ViewPokedex() {
+			if candidate.Pokemon.Name == reference {
+				matches = append(matches, candidate)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return CaughtPokemon{}, ErrNotCaught
+	case 1:
+		return matches[0], nil
+	}
+	ids := []string{}
+	for _, match := range matches {
+		ids = append(ids, "#"+strconv.Itoa(match.ID))
+	}
+	return CaughtPokemon{}, fmt.Errorf("%q %w, use one of %s", reference, ErrAmbiguous, strings.Join(ids, ", "))
+}
+
+// SetNickname names the caught Pokémon reference and returns it. An empty
+// nickname removes it.
+func SetNickname(reference, nickname string) (caught CaughtPokemon, err error) {
+	if _, err := strconv.Atoi(strings.TrimPrefix(nickname, "#")); err == nil {
+		return caught, fmt.Errorf("nickname %q cannot be a number", nickname)
+	}
+	caught, err = findCaught(reference)
+	if err != nil {
+		return caught, err
+	}
+	caught.Nickname = nickname
+	caughtPokemon[caught.ID] = caught
+	return caught, nil
+}
+
+// gainExperience adds points to caught, levelling it up as far as its growth
+// rate allows, and returns the number of levels gained.
+func (caught *CaughtPokemon) gainExperience(points int) (levels int) {
+	caught.Experience += points
+	for caught.Level < maxLevel && caught.Experience >= experienceForLevel(caught.GrowthRate, caught.Level+1) {
+		caught.Level++
+		caught.Happiness = min(caught.Happiness+levelUpHappiness, maxHappiness)
+		levels++
+	}
+	return levels
+}
+
+// experienceForLevel is the total experience needed to reach level. The
+// erratic and fluctuating growth rates are approximated by medium.
+func experienceForLevel(growthRate string, level int) int {
+	n := level
+	switch growthRate {
+	case "slow":
+		return 5 * n * n * n / 4
+	case "fast":
+		return 4 * n * n * n / 5
+	case "medium-slow":
+		return max(6*n*n*n/5-15*n*n+100*n-140, 0)
+	default:
+		return n * n * n
+	}
+}
+
+// battleExperience is the experience awarded for defeating a wild Pokémon
+// with the given base experience at level.
+func battleExperience(baseExperience, level int) int {
+	return max(baseExperience*level/7, 1)
+}
+
+// GiveItem makes the caught Pokémon reference hold item and returns it. An
+// empty item takes back whatever it was holding.
+func GiveItem(reference, item string) (caught CaughtPokemon, err error) {
+	caught, err = findCaught(reference)
+	if err != nil {
+		return caught, err
+	}
+	caught.HeldItem = item
+	caughtPokemon[caught.ID] = caught
+	return caught, nil
+}
+
+// sortedCaught returns the collection ordered by ID.
+func sortedCaught(collection map[int]CaughtPokemon) (sorted []CaughtPokemon) {
+	sorted = []CaughtPokemon{}
+	for _, caught := range collection {
+		sorted = append(sorted, caught)
+	}
+	slices.SortFunc(sorted, func(a, b CaughtPokemon) int {
+		return a.ID - b.ID
+	})
+	return sorted
+}
@@ -0,0 +1,88 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// resetCollection empties the collection for the duration of a test.
+func resetCollection(t *testing.T) {
+	t.Helper()
+	saved, savedNextID, savedParty, savedBoxes := caughtPokemon, nextID, party, boxes
+	caughtPokemon, nextID, party, boxes = map[int]CaughtPokemon{}, 1, []int{}, newBoxes()
+	t.Cleanup(func() {
+		caughtPokemon, nextID, party, boxes = saved, savedNextID, savedParty, savedBoxes
+	})
+}
+
+func TestFindCaught(t *testing.T) {
+	resetCollection(t)
+	first, _, _ := addCaught(CaughtPokemon{Pokemon: Pokemon{Name: "pikachu"}})
+	second, _, _ := addCaught(CaughtPokemon{Pokemon: Pokemon{Name: "pikachu"}})
+	addCaught(CaughtPokemon{Pokemon: Pokemon{Name: "eevee"}})
+
+	_, err := Inspect("pikachu")
+	if !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("Expected ErrAmbiguous for two pikachu, got %v", err)
+	}
+	caught, err := Inspect("#2")
+	if err != nil || caught.ID != second.ID {
+		t.Errorf("Expected the second pikachu by ID, got %+v, %v", caught, err)
+	}
+	_, err = SetNickname("1", "Sparky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	caught, err = Inspect("sparky")
+	if err != nil || caught.ID != first.ID || caught.Name() != "Sparky" {
+		t.Errorf("Expected the first pikachu by nickname, got %+v, %v", caught, err)
+	}
+	if caught, err := Inspect("eevee"); err != nil || caught.ID != 3 {
+		t.Errorf("Expected the only eevee by name, got %+v, %v", caught, err)
+	}
+	if _, err := Inspect("mew"); !errors.Is(err, ErrNotCaught) {
+		t.Errorf("Expected ErrNotCaught, got %v", err)
+	}
+	if _, err := SetNickname("eevee", "42"); err == nil {
+		t.Errorf("Expected a numeric nickname to be rejected")
+	}
+}
+
+// decodePokemon builds a Pokemon from an API-shaped JSON fragment.
+func decodePokemon(t *testing.T, data string) (pokemon Pokemon) {
+	t.Helper()
+	err := json.Unmarshal([]byte(data), &pokemon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pokemon
+}
+
+func TestCalculatedStats(t *testing.T) {
+	pokemon := decodePokemon(t, `{"stats": [
+		{"base_stat": 35, "stat": {"name": "hp"}},
+		{"base_stat": 55, "stat": {"name": "attack"}},
+		{"base_stat": 50, "stat": {"name": "special-attack"}}
+	]}`)
+	caught := CaughtPokemon{Pokemon: pokemon, Level: 50, Nature: "adamant"}
+	for _, name := range StatNames {
+		caught.IVs.set(name, maxIV)
+	}
+	stats := caught.CalculatedStats()
+	if stats.HP != 110 || stats.Attack != 82 || stats.SpecialAttack != 63 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestGainEffortIsCapped(t *testing.T) {
+	opponent := decodePokemon(t, `{"stats": [
+		{"effort": 3, "stat": {"name": "attack"}},
+		{"effort": 3, "stat": {"name": "hp"}}
+	]}`)
+	caught := CaughtPokemon{EVs: Stats{Attack: 251, Speed: 252, Defense: 6}}
+	caught.gainEffort(opponent)
+	if caught.EVs.Attack != 252 || caught.EVs.HP != 0 {
+		t.Errorf("Expected attack to reach the stat cap and hp the total cap, got %+v", caught.EVs)
+	}
+}
@@ -0,0 +1,60 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownMove    = errors.New("unknown move")
+	ErrUnknownAbility = errors.New("unknown ability")
+)
+
+func (client *Client) FetchAbility(ctx context.Context, name string) (ability AbilityInfo, err error) {
+	ability, err = fetchDecoded(ctx, client, client.decoded.abilities, client.endpoint("ability/"+name))
+	if err != nil {
+		return ability, notFoundAs(ErrUnknownAbility, name, err)
+	}
+	return ability, nil
+}
+
+// EnglishEffect describes what the move does, with its effect chance filled in.
+func (move MoveInfo) EnglishEffect() string {
+	effect := englishEffect(move.EffectEntries, move.FlavorTextEntries)
+	return strings.ReplaceAll(effect, "$effect_chance", strconv.Itoa(move.EffectChance))
+}
+
+// EnglishEffect describes what the ability does.
+func (ability AbilityInfo) EnglishEffect() string {
+	return englishEffect(ability.EffectEntries, ability.FlavorTextEntries)
+}
+
+// englishEffect prefers the short English effect, then the full one, then the
+// most recent English flavor text, for entries the API has not written effects for.
+func englishEffect(effects []EffectEntry, flavors []FlavorTextEntry) string {
+	for _, entry := range effects {
+		if entry.Language.Name != "en" {
+			continue
+		}
+		if entry.ShortEffect != "" {
+			return strings.Join(strings.Fields(entry.ShortEffect), " ")
+		}
+		if entry.Effect != "" {
+			return strings.Join(strings.Fields(entry.Effect), " ")
+		}
+	}
+	return englishFlavorText(flavors)
+}
+
+// englishFlavorText returns the most recent English flavor text with its hard
+// line breaks removed.
+func englishFlavorText(flavors []FlavorTextEntry) string {
+	for i := len(flavors) - 1; i >= 0; i-- {
+		if flavors[i].Language.Name == "en" {
+			return strings.Join(strings.Fields(flavors[i].FlavorText), " ")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFetchMoveEffect(t *testing.T) {
+	client, _ := newTestClient(t)
+	move, err := client.FetchMove(context.Background(), "thunder-shock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if effect := move.EnglishEffect(); effect != "Has a 10% chance to paralyze the target." {
+		t.Errorf("Unexpected effect %q", effect)
+	}
+	if len(move.LearnedByPokemon) != 2 || move.Pp != 30 {
+		t.Errorf("Unexpected move %+v", move)
+	}
+
+	_, err = client.FetchMove(context.Background(), "splashy-splash")
+	if !errors.Is(err, ErrUnknownMove) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrUnknownMove, got %v", err)
+	}
+}
+
+func TestFetchAbilityFallsBackToFlavorText(t *testing.T) {
+	client, _ := newTestClient(t)
+	ability, err := client.FetchAbility(context.Background(), "static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if effect := ability.EnglishEffect(); effect != "The Pokémon is charged with static electricity." {
+		t.Errorf("Unexpected effect %q", effect)
+	}
+	if len(ability.Pokemon) != 2 || !ability.Pokemon[1].IsHidden {
+		t.Errorf("Unexpected pokemon %+v", ability.Pokemon)
+	}
+
+	_, err = client.FetchAbility(context.Background(), "levitation")
+	if !errors.Is(err, ErrUnknownAbility) {
+		t.Errorf("Expected ErrUnknownAbility, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotInArea      = errors.New("does not live in this area")
+	ErrNotEncountered = errors.New("did not show up")
+)
+
+// Encounter summarises how a Pokémon can be met in an area across all game versions.
+type Encounter struct {
+	Pokemon string
+	// Chance is the best percentage chance of meeting it in any single version.
+	Chance   int
+	MinLevel int
+	MaxLevel int
+}
+
+func (client *Client) GetEncounters(ctx context.Context, area string) (encounters []Encounter, err error) {
+	info, err := client.fetchLocationInfo(ctx, area)
+	if err != nil {
+		return []Encounter{}, notFoundAs(ErrUnknownArea, area, err)
+	}
+	encounters = []Encounter{}
+	for _, pokemonEncounter := range info.PokemonEncounters {
+		encounter := Encounter{Pokemon: pokemonEncounter.Pokemon.Name}
+		for _, version := range pokemonEncounter.VersionDetails {
+			encounter.Chance = max(encounter.Chance, version.MaxChance)
+			for _, detail := range version.EncounterDetails {
+				if encounter.MinLevel == 0 || detail.MinLevel < encounter.MinLevel {
+					encounter.MinLevel = detail.MinLevel
+				}
+				encounter.MaxLevel = max(encounter.MaxLevel, detail.MaxLevel)
+			}
+		}
+		encounters = append(encounters, encounter)
+	}
+	return encounters, nil
+}
+
+// encounter looks for name in area and rolls whether it shows up, weighted by
+// its encounter chance. On success it returns the level it appeared at.
+func (client *Client) encounter(ctx context.Context, area, name string) (level int, err error) {
+	encounters, err := client.GetEncounters(ctx, area)
+	if err != nil {
+		return 0, err
+	}
+	for _, encounter := range encounters {
+		if encounter.Pokemon != name {
+			continue
+		}
+		if client.random.IntN(100) >= min(encounter.Chance, 100) {
+			return 0, fmt.Errorf("%s %w", name, ErrNotEncountered)
+		}
+		return encounter.MinLevel + client.random.IntN(encounter.MaxLevel-encounter.MinLevel+1), nil
+	}
+	return 0, fmt.Errorf("%s %w %s", name, ErrNotInArea, area)
+}
+
+func (client *Client) fetchLocationInfo(ctx context.Context, area string) (info LocationInfo, err error) {
+	return fetchDecoded(ctx, client, client.decoded.locationInfo, client.endpoint("location-area/"+area))
+}
@@ -0,0 +1,48 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetEncountersSummarisesVersions(t *testing.T) {
+	client, _ := newTestClient(t)
+	encounters, err := client.GetEncounters(context.Background(), "canalave-city-area")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encounters) != 2 {
+		t.Fatalf("Expected 2 encounters, got %v", len(encounters))
+	}
+	expected := Encounter{Pokemon: "tentacool", Chance: 60, MinLevel: 15, MaxLevel: 30}
+	if encounters[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, encounters[0])
+	}
+}
+
+func TestCatchPokemonRestrictedToArea(t *testing.T) {
+	client, _ := newTestClient(t)
+	resetCollection(t)
+
+	client.SetRandom(&sequenceRandom{values: []int{99}})
+	_, err := client.CatchPokemon(context.Background(), "tentacool", CatchOptions{Area: "canalave-city-area"})
+	if !errors.Is(err, ErrNotEncountered) {
+		t.Errorf("Expected ErrNotEncountered, got %v", err)
+	}
+
+	_, err = client.CatchPokemon(context.Background(), "mewtwo", CatchOptions{Area: "canalave-city-area"})
+	if !errors.Is(err, ErrNotInArea) {
+		t.Errorf("Expected ErrNotInArea, got %v", err)
+	}
+
+	// Appear roll, level roll, then four successful shake checks.
+	client.SetRandom(&sequenceRandom{values: []int{0, 5, 0}})
+	result, err := client.CatchPokemon(context.Background(), "tentacool", CatchOptions{Area: "canalave-city-area"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Caught || result.Level != 20 {
+		t.Errorf("Expected a level 20 catch, got %+v", result)
+	}
+}
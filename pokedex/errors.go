@@ -0,0 +1,54 @@
+package pokedex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNotFound matches any error caused by the API answering 404.
+var ErrNotFound = errors.New("not found")
+
+var (
+	ErrUnknownArea    = errors.New("unknown area")
+	ErrUnknownPokemon = errors.New("unknown pokemon")
+	ErrNotCaught      = errors.New("you have not caught that pokemon")
+)
+
+// notFoundAs tags a 404 with a more specific sentinel, keeping the original
+// error in the chain.
+func notFoundAs(sentinel error, name string, err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%w %q: %w", sentinel, name, err)
+	}
+	return err
+}
+
+// StatusError reports a response that was received but was not a success.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Unexpected status %d %s from %s", e.StatusCode, http.StatusText(e.StatusCode), e.URL)
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// TransportError reports a request that never produced a response, including
+// one aborted by its context.
+type TransportError struct {
+	URL string
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("Error getting data from %s: %v", e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,198 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrFinalStage   = errors.New("does not evolve any further")
+	ErrCannotEvolve = errors.New("cannot evolve yet")
+)
+
+// now is the clock time-of-day conditions are checked against.
+var now = time.Now
+
+// EvolveOptions describes what the trainer does to trigger an evolution.
+type EvolveOptions struct {
+	// Item is used on the Pokémon, e.g. "thunder-stone".
+	Item string
+	// Trade evolves the Pokémon as though it had just been traded.
+	Trade bool
+}
+
+type EvolveResult struct {
+	ID        int    `json:"id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Condition string `json:"condition"`
+	Level     int    `json:"level"`
+}
+
+// Evolve replaces the caught Pokémon reference with the first next stage of its
+// evolution chain whose conditions it meets. It keeps its level, experience
+// and friendship; an item held to trigger the evolution is used up.
+func (client *Client) Evolve(ctx context.Context, reference string, options EvolveOptions) (result EvolveResult, err error) {
+	caught, err := Inspect(reference)
+	if err != nil {
+		return result, err
+	}
+	species := caught.Pokemon.Species.Name
+	root, err := client.GetEvolutionChain(ctx, species)
+	if err != nil {
+		return result, err
+	}
+	node, exists := root.Find(species)
+	if !exists || len(node.EvolvesTo) == 0 {
+		return result, fmt.Errorf("%q %w", reference, ErrFinalStage)
+	}
+	reasons := []string{}
+	for _, next := range node.EvolvesTo {
+		for _, detail := range next.Conditions {
+			missing := caught.unmetConditions(detail, options, now())
+			if len(missing) > 0 {
+				reasons = append(reasons, next.Species+" needs "+strings.Join(missing, ", "))
+				continue
+			}
+			pokemon, err := client.defaultPokemon(ctx, next.Species)
+			if err != nil {
+				return result, err
+			}
+			if detail.HeldItem != nil {
+				caught.HeldItem = ""
+			}
+			from := caught.Name()
+			caught.Pokemon = pokemon
+			caughtPokemon[caught.ID] = caught
+			return EvolveResult{ID: caught.ID, From: from, To: pokemon.Name, Condition: detail.String(), Level: caught.Level}, nil
+		}
+	}
+	return result, fmt.Errorf("%q %w: %s", reference, ErrCannotEvolve, strings.Join(reasons, "; "))
+}
+
+// unmetConditions lists the parts of detail that caught does not meet at the
+// given time, worded like EvolutionDetail.String. Conditions the collection
+// does not track, such as beauty or party members, are never met.
+func (caught CaughtPokemon) unmetConditions(detail EvolutionDetail, options EvolveOptions, at time.Time) (missing []string) {
+	missing = []string{}
+	switch detail.Trigger.Name {
+	case "level-up":
+		if caught.Level < detail.MinLevel {
+			missing = append(missing, fmt.Sprintf("level %d", detail.MinLevel))
+		}
+	case "use-item":
+		if detail.Item == nil || options.Item != detail.Item.Name {
+			missing = append(missing, detail.String())
+			return missing
+		}
+	case "trade":
+		if !options.Trade {
+			missing = append(missing, "trade")
+		}
+		if detail.TradeSpecies != nil {
+			missing = append(missing, "for "+detail.TradeSpecies.Name)
+		}
+	default:
+		missing = append(missing, detail.String())
+		return missing
+	}
+	if detail.HeldItem != nil && caught.HeldItem != detail.HeldItem.Name {
+		missing = append(missing, "holding "+detail.HeldItem.Name)
+	}
+	if caught.Happiness < detail.MinHappiness {
+		missing = append(missing, fmt.Sprintf("friendship %d", detail.MinHappiness))
+	}
+	if detail.KnownMove != nil && !slices.Contains(levelUpMoves(caught.Pokemon, caught.Level), detail.KnownMove.Name) {
+		missing = append(missing, "knowing "+detail.KnownMove.Name)
+	}
+	if detail.TimeOfDay != "" && !isTimeOfDay(detail.TimeOfDay, at) {
+		missing = append(missing, "the "+detail.TimeOfDay)
+	}
+	if detail.RelativePhysicalStats != 0 && caught.relativePhysicalStats() != detail.RelativePhysicalStats {
+		if detail.RelativePhysicalStats > 0 {
+			missing = append(missing, "attack > defense")
+		} else {
+			missing = append(missing, "attack < defense")
+		}
+	}
+	if detail.MinAffection > 0 {
+		missing = append(missing, fmt.Sprintf("affection %d", detail.MinAffection))
+	}
+	if detail.MinBeauty > 0 {
+		missing = append(missing, fmt.Sprintf("beauty %d", detail.MinBeauty))
+	}
+	if detail.KnownMoveType != nil {
+		missing = append(missing, "a "+detail.KnownMoveType.Name+" move")
+	}
+	if detail.Location != nil {
+		missing = append(missing, "to be at "+detail.Location.Name)
+	}
+	if detail.PartySpecies != nil {
+		missing = append(missing, detail.PartySpecies.Name+" in the party")
+	}
+	if detail.PartyType != nil {
+		missing = append(missing, "a "+detail.PartyType.Name+" type in the party")
+	}
+	switch detail.Gender {
+	case 1:
+		missing = append(missing, "to be female")
+	case 2:
+		missing = append(missing, "to be male")
+	}
+	if detail.NeedsOverworldRain {
+		missing = append(missing, "rain")
+	}
+	if detail.TurnUpsideDown {
+		missing = append(missing, "the console upside down")
+	}
+	return missing
+}
+
+// relativePhysicalStats compares base attack with base defense as the
+// evolution API does: 1 if attack is higher, -1 if lower, 0 if equal.
+func (caught CaughtPokemon) relativePhysicalStats() int {
+	base := baseStats(caught.Pokemon)
+	switch {
+	case base.Attack > base.Defense:
+		return 1
+	case base.Attack < base.Defense:
+		return -1
+	}
+	return 0
+}
+
+// isTimeOfDay reports whether at falls in period. Dusk is the last hour of the day.
+func isTimeOfDay(period string, at time.Time) bool {
+	hour := at.Hour()
+	switch period {
+	case "day":
+		return hour >= 6 && hour < 18
+	case "night":
+		return hour < 6 || hour >= 18
+	case "dusk":
+		return hour == 17
+	}
+	return false
+}
+
+// defaultPokemon fetches the default form of species, e.g. wormadam-plant for wormadam.
+func (client *Client) defaultPokemon(ctx context.Context, species string) (pokemon Pokemon, err error) {
+	pokemon, err = client.fetchPokemon(ctx, species)
+	if !errors.Is(err, ErrNotFound) {
+		return pokemon, err
+	}
+	info, speciesErr := client.fetchSpecies(ctx, species)
+	if speciesErr != nil {
+		return pokemon, speciesErr
+	}
+	for _, variety := range info.Varieties {
+		if variety.IsDefault {
+			return client.fetchPokemon(ctx, variety.Pokemon.Name)
+		}
+	}
+	return pokemon, err
+}
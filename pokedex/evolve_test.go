@@ -0,0 +1,101 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEvolve(t *testing.T) {
+	client, _ := newTestClient(t)
+	eevee := Pokemon{Name: "eevee"}
+	eevee.Species.Name = "eevee"
+	resetCollection(t)
+	addCaught(CaughtPokemon{Nickname: "Bubbles", Pokemon: eevee, Level: 20, Happiness: 70})
+
+	_, err := client.Evolve(context.Background(), "eevee", EvolveOptions{})
+	if !errors.Is(err, ErrCannotEvolve) {
+		t.Fatalf("Expected ErrCannotEvolve without a stone, got %v", err)
+	}
+
+	result, err := client.Evolve(context.Background(), "bubbles", EvolveOptions{Item: "water-stone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.From != "Bubbles" || result.To != "vaporeon" || result.Condition != "use water-stone" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if _, err := Inspect("eevee"); err == nil {
+		t.Errorf("Expected eevee to be replaced")
+	}
+	vaporeon, err := Inspect("Bubbles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vaporeon.ID != result.ID || vaporeon.Level != 20 || vaporeon.Pokemon.BaseExperience != 184 {
+		t.Errorf("Expected vaporeon to keep its level, got %+v", vaporeon)
+	}
+
+	_, err = client.Evolve(context.Background(), "vaporeon", EvolveOptions{})
+	if !errors.Is(err, ErrFinalStage) {
+		t.Errorf("Expected ErrFinalStage, got %v", err)
+	}
+}
+
+func TestEvolveKeepsOtherCaughtOfTargetSpecies(t *testing.T) {
+	client, _ := newTestClient(t)
+	eevee := Pokemon{Name: "eevee"}
+	eevee.Species.Name = "eevee"
+	resetCollection(t)
+	first, _, _ := addCaught(CaughtPokemon{Pokemon: eevee, Level: 20})
+	second, _, _ := addCaught(CaughtPokemon{Pokemon: eevee, Level: 25})
+
+	for _, caught := range []CaughtPokemon{first, second} {
+		_, err := client.Evolve(context.Background(), "#"+strconv.Itoa(caught.ID), EvolveOptions{Item: "water-stone"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	collection := ViewPokedex()
+	if len(collection) != 2 {
+		t.Fatalf("Expected both evolved pokemon to be kept, got %d", len(collection))
+	}
+	for i, caught := range []CaughtPokemon{first, second} {
+		if collection[i].ID != caught.ID || collection[i].Pokemon.Name != "vaporeon" || collection[i].Level != caught.Level {
+			t.Errorf("Expected #%d to be a level %d vaporeon, got %+v", caught.ID, caught.Level, collection[i])
+		}
+	}
+}
+
+func TestUnmetConditions(t *testing.T) {
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	detail := EvolutionDetail{MinHappiness: 160, TimeOfDay: "day"}
+	detail.Trigger.Name = "level-up"
+
+	caught := CaughtPokemon{Level: 5, Happiness: 70}
+	missing := caught.unmetConditions(detail, EvolveOptions{}, noon)
+	if len(missing) != 1 || missing[0] != "friendship 160" {
+		t.Errorf("Expected only friendship missing, got %v", missing)
+	}
+	caught.Happiness = 160
+	if missing := caught.unmetConditions(detail, EvolveOptions{}, noon); len(missing) != 0 {
+		t.Errorf("Expected all conditions met, got %v", missing)
+	}
+	if missing := caught.unmetConditions(detail, EvolveOptions{}, noon.Add(10*time.Hour)); len(missing) != 1 {
+		t.Errorf("Expected the time of day to be missing at night, got %v", missing)
+	}
+}
+
+func TestGainExperience(t *testing.T) {
+	caught := CaughtPokemon{Level: 5, Experience: 125, Happiness: 70}
+	levels := caught.gainExperience(216 - 125)
+	if levels != 1 || caught.Level != 6 || caught.Happiness != 75 {
+		t.Errorf("Expected to reach level 6, got %+v after %d levels", caught, levels)
+	}
+	caught.gainExperience(1_000_000_000)
+	if caught.Level != 100 {
+		t.Errorf("Expected level to stop at 100, got %d", caught.Level)
+	}
+}
@@ -0,0 +1,53 @@
+package pokedex
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup coalesces concurrent fetches of the same key into one, so a
+// burst of identical lookups costs a single request. The zero value is ready to use.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	data []byte
+	err  error
+	// abandoned records that the ctx of the caller running the fetch was done
+	// by the time it returned, so its error need not apply to anyone else.
+	abandoned bool
+}
+
+// do runs fetch for key unless a fetch of key is already in flight, in which
+// case it waits for that one and reports whether its caller abandoned it. A
+// waiting caller gives up when its own ctx is done, without affecting the fetch.
+func (group *flightGroup) do(ctx context.Context, key string, fetch func() ([]byte, error)) (data []byte, abandoned bool, err error) {
+	group.mu.Lock()
+	if group.flights == nil {
+		group.flights = map[string]*flight{}
+	}
+	if current, exists := group.flights[key]; exists {
+		group.mu.Unlock()
+		select {
+		case <-current.done:
+			return current.data, current.abandoned, current.err
+		case <-ctx.Done():
+			return []byte{}, false, ctx.Err()
+		}
+	}
+	current := &flight{done: make(chan struct{})}
+	group.flights[key] = current
+	group.mu.Unlock()
+
+	current.data, current.err = fetch()
+	current.abandoned = ctx.Err() != nil
+
+	group.mu.Lock()
+	delete(group.flights, key)
+	group.mu.Unlock()
+	close(current.done)
+	return current.data, false, current.err
+}
@@ -26,17 +26,11 @@ type LocationInfo struct {
 			} `json:"version"`
 		} `json:"version_details"`
 	} `json:"encounter_method_rates"`
-	GameIndex int           `json:"game_index"`
-	ID        int           `json:"id"`
-	Location  PokemonEntity `json:"location"`
-	Name      string        `json:"name"`
-	Names     []struct {
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-		Name string `json:"name"`
-	} `json:"names"`
+	GameIndex         int             `json:"game_index"`
+	ID                int             `json:"id"`
+	Location          PokemonEntity   `json:"location"`
+	Name              string          `json:"name"`
+	Names             []LocalizedName `json:"names"`
 	PokemonEncounters []struct {
 		Pokemon        PokemonEntity `json:"pokemon"`
 		VersionDetails []struct {
@@ -331,3 +325,129 @@ type Pokemon struct {
 	} `json:"types"`
 	Weight int `json:"weight"`
 }
+
+type MoveInfo struct {
+	Accuracy          int               `json:"accuracy"`
+	DamageClass       PokemonEntity     `json:"damage_class"`
+	EffectChance      int               `json:"effect_chance"`
+	EffectEntries     []EffectEntry     `json:"effect_entries"`
+	FlavorTextEntries []FlavorTextEntry `json:"flavor_text_entries"`
+	Generation        PokemonEntity     `json:"generation"`
+	ID                int               `json:"id"`
+	LearnedByPokemon  []PokemonEntity   `json:"learned_by_pokemon"`
+	Name              string            `json:"name"`
+	Names             []LocalizedName   `json:"names"`
+	Power             int               `json:"power"`
+	Pp                int               `json:"pp"`
+	Priority          int               `json:"priority"`
+	Target            PokemonEntity     `json:"target"`
+	Type              PokemonEntity     `json:"type"`
+}
+
+type AbilityInfo struct {
+	EffectEntries     []EffectEntry     `json:"effect_entries"`
+	FlavorTextEntries []FlavorTextEntry `json:"flavor_text_entries"`
+	Generation        PokemonEntity     `json:"generation"`
+	ID                int               `json:"id"`
+	IsMainSeries      bool              `json:"is_main_series"`
+	Name              string            `json:"name"`
+	Pokemon           []struct {
+		IsHidden bool          `json:"is_hidden"`
+		Pokemon  PokemonEntity `json:"pokemon"`
+		Slot     int           `json:"slot"`
+	} `json:"pokemon"`
+}
+
+type LocalizedName struct {
+	Language PokemonEntity `json:"language"`
+	Name     string        `json:"name"`
+}
+
+type EffectEntry struct {
+	Effect      string        `json:"effect"`
+	Language    PokemonEntity `json:"language"`
+	ShortEffect string        `json:"short_effect"`
+}
+
+// FlavorTextEntry is a Pokédex, move or ability description. Species entries
+// name the game's Version, move and ability entries its VersionGroup.
+type FlavorTextEntry struct {
+	FlavorText   string        `json:"flavor_text"`
+	Language     PokemonEntity `json:"language"`
+	Version      PokemonEntity `json:"version"`
+	VersionGroup PokemonEntity `json:"version_group"`
+}
+
+type TypeInfo struct {
+	DamageRelations struct {
+		DoubleDamageFrom []PokemonEntity `json:"double_damage_from"`
+		DoubleDamageTo   []PokemonEntity `json:"double_damage_to"`
+		HalfDamageFrom   []PokemonEntity `json:"half_damage_from"`
+		HalfDamageTo     []PokemonEntity `json:"half_damage_to"`
+		NoDamageFrom     []PokemonEntity `json:"no_damage_from"`
+		NoDamageTo       []PokemonEntity `json:"no_damage_to"`
+	} `json:"damage_relations"`
+	ID    int             `json:"id"`
+	Name  string          `json:"name"`
+	Names []LocalizedName `json:"names"`
+}
+
+type SpeciesInfo struct {
+	BaseHappiness  int `json:"base_happiness"`
+	CaptureRate    int `json:"capture_rate"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	EvolvesFromSpecies *PokemonEntity    `json:"evolves_from_species"`
+	FlavorTextEntries  []FlavorTextEntry `json:"flavor_text_entries"`
+	Genera             []struct {
+		Genus    string        `json:"genus"`
+		Language PokemonEntity `json:"language"`
+	} `json:"genera"`
+	Generation  PokemonEntity   `json:"generation"`
+	GrowthRate  PokemonEntity   `json:"growth_rate"`
+	Habitat     *PokemonEntity  `json:"habitat"`
+	ID          int             `json:"id"`
+	IsBaby      bool            `json:"is_baby"`
+	IsLegendary bool            `json:"is_legendary"`
+	IsMythical  bool            `json:"is_mythical"`
+	Name        string          `json:"name"`
+	Names       []LocalizedName `json:"names"`
+	Varieties   []struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   PokemonEntity `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+type EvolutionChainInfo struct {
+	Chain ChainLink `json:"chain"`
+	ID    int       `json:"id"`
+}
+
+type ChainLink struct {
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+	IsBaby           bool              `json:"is_baby"`
+	Species          PokemonEntity     `json:"species"`
+}
+
+type EvolutionDetail struct {
+	Gender                int            `json:"gender"`
+	HeldItem              *PokemonEntity `json:"held_item"`
+	Item                  *PokemonEntity `json:"item"`
+	KnownMove             *PokemonEntity `json:"known_move"`
+	KnownMoveType         *PokemonEntity `json:"known_move_type"`
+	Location              *PokemonEntity `json:"location"`
+	MinAffection          int            `json:"min_affection"`
+	MinBeauty             int            `json:"min_beauty"`
+	MinHappiness          int            `json:"min_happiness"`
+	MinLevel              int            `json:"min_level"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	PartySpecies          *PokemonEntity `json:"party_species"`
+	PartyType             *PokemonEntity `json:"party_type"`
+	RelativePhysicalStats int            `json:"relative_physical_stats"`
+	TimeOfDay             string         `json:"time_of_day"`
+	TradeSpecies          *PokemonEntity `json:"trade_species"`
+	Trigger               PokemonEntity  `json:"trigger"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
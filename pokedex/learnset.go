@@ -0,0 +1,141 @@
+package pokedex
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownMethod = errors.New("unknown learn method")
+
+// LearnMethods lists the ways of learning a move a learnset can be filtered
+// by, in the order it is sorted by.
+var LearnMethods = []string{"level-up", "machine", "egg", "tutor"}
+
+type LearnsetOptions struct {
+	// Game is a version group such as "red-blue". Without one, each move is
+	// shown as learned in the most recent game the API lists for it.
+	Game string
+	// Method keeps only moves learned that way, e.g. "machine".
+	Method string
+	// Details fetches power, accuracy and PP of every move from /move/.
+	Details bool
+}
+
+// LearnableMove is one entry of a learnset. Power, Accuracy, PP, Type and
+// DamageClass are only set when details were requested.
+type LearnableMove struct {
+	Name        string `json:"name"`
+	Method      string `json:"method"`
+	Level       int    `json:"level"`
+	Game        string `json:"game"`
+	Power       int    `json:"power,omitempty"`
+	Accuracy    int    `json:"accuracy,omitempty"`
+	PP          int    `json:"pp,omitempty"`
+	Type        string `json:"type,omitempty"`
+	DamageClass string `json:"damage_class,omitempty"`
+}
+
+// GetLearnset returns the moves a Pokémon can learn, sorted by learn method,
+// then level, then name. A bare number such as "25" is a national dex number,
+// while "#25", a nickname, or a name only one caught Pokémon has refers to
+// that caught Pokémon. Any other name is looked up as a Pokémon.
+func (client *Client) GetLearnset(ctx context.Context, name string, options LearnsetOptions) (moves []LearnableMove, err error) {
+	if options.Method != "" && !slices.Contains(LearnMethods, options.Method) {
+		return []LearnableMove{}, fmt.Errorf("%w %q, expected one of %s", ErrUnknownMethod, options.Method, strings.Join(LearnMethods, ", "))
+	}
+	if _, err := strconv.Atoi(name); err != nil {
+		caught, err := findCaught(name)
+		if err == nil {
+			return client.learnset(ctx, caught.Pokemon, options)
+		}
+		if strings.HasPrefix(name, "#") {
+			return []LearnableMove{}, err
+		}
+	}
+	pokemon, err := client.fetchPokemon(ctx, name)
+	if err != nil {
+		return []LearnableMove{}, notFoundAs(ErrUnknownPokemon, name, err)
+	}
+	return client.learnset(ctx, pokemon, options)
+}
+
+func (client *Client) learnset(ctx context.Context, pokemon Pokemon, options LearnsetOptions) (moves []LearnableMove, err error) {
+	moves = []LearnableMove{}
+	for _, move := range pokemon.Moves {
+		// Keep one entry per learn method from the most recent game, going by
+		// version group ID as the API does not promise to list them in order.
+		latest := map[string]LearnableMove{}
+		latestGroup := map[string]int{}
+		for _, detail := range move.VersionGroupDetails {
+			if options.Game != "" && detail.VersionGroup.Name != options.Game {
+				continue
+			}
+			if options.Method != "" && detail.MoveLearnMethod.Name != options.Method {
+				continue
+			}
+			group := versionGroupID(detail.VersionGroup.URL)
+			if current, exists := latestGroup[detail.MoveLearnMethod.Name]; exists && group < current {
+				continue
+			}
+			latestGroup[detail.MoveLearnMethod.Name] = group
+			latest[detail.MoveLearnMethod.Name] = LearnableMove{
+				Name:   move.Move.Name,
+				Method: detail.MoveLearnMethod.Name,
+				Level:  detail.LevelLearnedAt,
+				Game:   detail.VersionGroup.Name,
+			}
+		}
+		for _, learnable := range latest {
+			moves = append(moves, learnable)
+		}
+	}
+	slices.SortFunc(moves, compareLearnableMoves)
+	if !options.Details {
+		return moves, nil
+	}
+	for i, learnable := range moves {
+		move, err := client.FetchMove(ctx, learnable.Name)
+		if err != nil {
+			return moves, err
+		}
+		moves[i].Power = move.Power
+		moves[i].Accuracy = move.Accuracy
+		moves[i].PP = move.Pp
+		moves[i].Type = move.Type.Name
+		moves[i].DamageClass = move.DamageClass.Name
+	}
+	return moves, nil
+}
+
+// versionGroupID reads the ID from a version group URL such as
+// ".../version-group/20/", or returns 0 when it has none.
+func versionGroupID(url string) int {
+	id, err := strconv.Atoi(path.Base(strings.TrimSuffix(url, "/")))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func compareLearnableMoves(a, b LearnableMove) int {
+	return cmp.Or(
+		cmp.Compare(methodRank(a.Method), methodRank(b.Method)),
+		cmp.Compare(a.Method, b.Method),
+		cmp.Compare(a.Level, b.Level),
+		cmp.Compare(a.Name, b.Name),
+	)
+}
+
+// methodRank orders the methods in LearnMethods first and any others after.
+func methodRank(method string) int {
+	if rank := slices.Index(LearnMethods, method); rank >= 0 {
+		return rank
+	}
+	return len(LearnMethods)
+}
@@ -0,0 +1,67 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetLearnset(t *testing.T) {
+	client, hits := newTestClient(t)
+	moves, err := client.GetLearnset(context.Background(), "tentacool", LearnsetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []LearnableMove{
+		{Name: "poison-sting", Method: "level-up", Level: 1, Game: "sword-shield"},
+		{Name: "supersonic", Method: "level-up", Level: 8, Game: "sword-shield"},
+		{Name: "hydro-pump", Method: "level-up", Level: 55, Game: "sword-shield"},
+		{Name: "surf", Method: "machine", Level: 0, Game: "red-blue"},
+	}
+	if len(moves) != len(expected) {
+		t.Fatalf("Expected %d moves, got %+v", len(expected), moves)
+	}
+	for i := range expected {
+		if moves[i] != expected[i] {
+			t.Errorf("Expected %+v at %d, got %+v", expected[i], i, moves[i])
+		}
+	}
+	if hits["/api/v2/move/surf"] != 0 {
+		t.Errorf("Fetched move details that were not requested")
+	}
+}
+
+func TestGetLearnsetFiltersAndEnriches(t *testing.T) {
+	client, _ := newTestClient(t)
+	moves, err := client.GetLearnset(context.Background(), "tentacool", LearnsetOptions{Game: "red-blue", Method: "level-up", Details: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moves) != 2 || moves[1].Name != "supersonic" || moves[1].Level != 7 {
+		t.Fatalf("Expected the red-blue level-up moves, got %+v", moves)
+	}
+	if moves[0].Power != 15 || moves[0].Accuracy != 100 || moves[0].Type != "poison" {
+		t.Errorf("Expected poison-sting details, got %+v", moves[0])
+	}
+}
+
+func TestGetLearnsetReferences(t *testing.T) {
+	client, hits := newTestClient(t)
+	resetCollection(t)
+	vaporeon := Pokemon{Name: "vaporeon"}
+	addCaught(CaughtPokemon{Nickname: "Bubbles", Pokemon: vaporeon})
+
+	if _, err := client.GetLearnset(context.Background(), "#1", LearnsetOptions{}); err != nil {
+		t.Errorf("Expected #1 to be the caught vaporeon, got %v", err)
+	}
+	if _, err := client.GetLearnset(context.Background(), "#2", LearnsetOptions{}); !errors.Is(err, ErrNotCaught) {
+		t.Errorf("Expected ErrNotCaught for an unused caught ID, got %v", err)
+	}
+	client.GetLearnset(context.Background(), "1", LearnsetOptions{})
+	if hits["/api/v2/pokemon/1"] != 1 {
+		t.Errorf("Expected a bare number to be looked up as a national dex number")
+	}
+	if _, err := client.GetLearnset(context.Background(), "tentacool", LearnsetOptions{Method: "teleport"}); !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("Expected ErrUnknownMethod, got %v", err)
+	}
+}
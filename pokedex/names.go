@@ -0,0 +1,96 @@
+package pokedex
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Languages lists the language codes the API has names in.
+var Languages = []string{"en", "ja", "ja-Hrkt", "roomaji", "ko", "zh-Hans", "zh-Hant", "fr", "de", "es", "it"}
+
+// NameKind is the kind of resource a slug names.
+type NameKind int
+
+const (
+	LocationAreaName NameKind = iota
+	PokemonName
+	MoveName
+	TypeName
+)
+
+// DefaultLocalizeWorkers bounds how many names LocalizedNames fetches at once.
+const DefaultLocalizeWorkers = 8
+
+// LocalizedName returns the name of the resource slug in language. It falls
+// back to slug when language is empty, the resource has no name in that
+// language, or it cannot be fetched, and only fails once ctx is done.
+// Pokémon are named after their species.
+func (client *Client) LocalizedName(ctx context.Context, kind NameKind, slug, language string) (name string, err error) {
+	if language == "" || slug == "" {
+		return slug, nil
+	}
+	var names []LocalizedName
+	switch kind {
+	case LocationAreaName:
+		var info LocationInfo
+		info, err = client.fetchLocationInfo(ctx, slug)
+		names = info.Names
+	case PokemonName:
+		var species SpeciesInfo
+		species, err = client.GetSpecies(ctx, slug)
+		names = species.Names
+	case MoveName:
+		var move MoveInfo
+		move, err = client.FetchMove(ctx, slug)
+		names = move.Names
+	case TypeName:
+		var info TypeInfo
+		info, err = client.fetchType(ctx, slug)
+		names = info.Names
+	}
+	if err != nil {
+		return slug, ctx.Err()
+	}
+	return pickName(names, language, slug), nil
+}
+
+// LocalizedNames names each of slugs in language like LocalizedName,
+// fetching workers at a time, so a listing costs one round of requests
+// rather than one per line. It stops and returns ctx's error once ctx is done.
+func (client *Client) LocalizedNames(ctx context.Context, kind NameKind, slugs []string, language string, workers int) (names map[string]string, err error) {
+	if workers <= 0 {
+		workers = DefaultLocalizeWorkers
+	}
+	names = map[string]string{}
+	var mu sync.Mutex
+	forEach(ctx, slugs, workers, func(slug string) {
+		name, err := client.LocalizedName(ctx, kind, slug, language)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		names[slug] = name
+		mu.Unlock()
+	})
+	if err := ctx.Err(); err != nil {
+		return map[string]string{}, err
+	}
+	return names, nil
+}
+
+// pickName returns the name in language, accepting a variant of it such as
+// ja-Hrkt for ja when there is no exact match, or fallback.
+func pickName(names []LocalizedName, language, fallback string) string {
+	for _, name := range names {
+		if strings.EqualFold(name.Language.Name, language) && name.Name != "" {
+			return name.Name
+		}
+	}
+	for _, name := range names {
+		if strings.HasPrefix(strings.ToLower(name.Language.Name), strings.ToLower(language)+"-") && name.Name != "" {
+			return name.Name
+		}
+	}
+	return fallback
+}
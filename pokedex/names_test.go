@@ -0,0 +1,56 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLocalizedName(t *testing.T) {
+	client, hits := newTestClient(t)
+	ctx := context.Background()
+	cases := []struct {
+		kind     NameKind
+		slug     string
+		language string
+		expected string
+	}{
+		{LocationAreaName, "canalave-city-area", "en", "Canalave City"},
+		{LocationAreaName, "canalave-city-area", "fr", "canalave-city-area"},
+		{PokemonName, "tentacool", "ja", "メノクラゲ"},
+		{PokemonName, "tentacool", "de", "Tentacool"},
+		{TypeName, "water", "fr", "Eau"},
+		{MoveName, "splashy-splash", "fr", "splashy-splash"},
+		{TypeName, "fire", "", "fire"},
+	}
+	for _, c := range cases {
+		actual, err := client.LocalizedName(ctx, c.kind, c.slug, c.language)
+		if err != nil || actual != c.expected {
+			t.Errorf("Expected %q for %s in %q, got %q, %v", c.expected, c.slug, c.language, actual, err)
+		}
+	}
+	if hits["/api/v2/type/fire"] != 0 {
+		t.Errorf("Fetched a name without a language set")
+	}
+}
+
+func TestLocalizedNames(t *testing.T) {
+	client, hits := newTestClient(t)
+	names, err := client.LocalizedNames(context.Background(), TypeName, []string{"water", "fire", "water"}, "fr", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names["water"] != "Eau" || names["fire"] != "fire" {
+		t.Errorf("Expected water as Eau and fire as its slug, got %v", names)
+	}
+	if hits["/api/v2/type/water"] != 1 {
+		t.Errorf("Expected one request for water, got %d", hits["/api/v2/type/water"])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.LocalizedNames(ctx, PokemonName, []string{"tentacool"}, "ja", 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled lookup to fail with context.Canceled, got %v", err)
+	}
+}
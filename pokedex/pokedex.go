@@ -1,67 +1,39 @@
 package pokedex
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"math/rand/v2"
-	"net/http"
+	"context"
 	"strings"
-	"time"
-
-	pokecache "github.com/anantashahane/pokedex/pokecache"
 )
 
-var cache = pokecache.NewCache(time.Minute * 2)
-var caughtPokemon = map[string]Pokemon{}
-
-func fetchData(url string) (body []byte, err error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return []byte{}, fmt.Errorf("Error getting data from %s: %w", url, err)
-	}
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return []byte{}, fmt.Errorf("Error reading body from %s: %w", url, err)
-	}
-	defer resp.Body.Close()
-	return data, err
+// LocationPage is one page of location area names along with the URLs of its neighbours.
+type LocationPage struct {
+	Locations []string `json:"locations"`
+	Previous  string   `json:"previous"`
+	Next      string   `json:"next"`
 }
 
-func GetLocations(url string) (locations []string, previous, next string) {
-	locationsData, err := fetchLocationsData(url)
+func (client *Client) GetLocations(ctx context.Context, url string) (page LocationPage, err error) {
+	locationsData, err := client.fetchLocationsData(ctx, url)
 	if err != nil {
-		return []string{}, "", ""
+		return LocationPage{}, err
 	}
-	locations = []string{}
+	page = LocationPage{Locations: []string{}, Previous: locationsData.Previous, Next: locationsData.Next}
 
 	for _, location := range locationsData.Results {
-		locations = append(locations, location.Name)
+		page.Locations = append(page.Locations, location.Name)
 	}
-	return locations, locationsData.Previous, locationsData.Next
+	return page, nil
 }
 
-func fetchLocationsData(url string) (locations PokeLocations, err error) {
+func (client *Client) fetchLocationsData(ctx context.Context, url string) (locations PokeLocations, err error) {
 	if url == "" {
-		url = "https://pokeapi.co/api/v2/location-area/?offset=0&limit=20"
+		url = client.endpoint("location-area/?offset=0&limit=20")
 	}
-	data, available := cache.Get(url)
-	if !available {
-		data, err = fetchData(url)
-		if err != nil {
-			return PokeLocations{}, err
-		}
-	}
-	cache.Add(url, data)
-
-	var locationData PokeLocations
-	err = json.Unmarshal(data, &locationData)
-	if err != nil {
-		return PokeLocations{}, fmt.Errorf("Error decoding data received from %s: %w", url, err)
-	}
-	return locationData, nil
+	return fetchDecoded(ctx, client, client.decoded.locations, client.resolve(url))
 }
 
+// CleanInput splits text into lowercase words. The REPL now parses quoting
+// itself; this stays for scripts built on the package.
 func CleanInput(text string) (word []string) {
 	words := strings.Fields(strings.ToLower(text))
 	if len(words) == 0 {
@@ -70,33 +42,24 @@ func CleanInput(text string) (word []string) {
 	return words
 }
 
-func GetPokemons(url string) (pokemons []string, available bool) {
-	pokemonEntities, err := fetchPokemons(url)
-	available = true
+// GetPokemons lists the Pokémon found in area. An area without encounters
+// yields an empty slice, while an area the API does not know yields ErrUnknownArea.
+func (client *Client) GetPokemons(ctx context.Context, area string) (pokemons []string, err error) {
+	pokemonEntities, err := client.fetchPokemons(ctx, area)
 	if err != nil {
-		fmt.Printf("%s", err)
-		available = false
-		return pokemons, available
+		return []string{}, notFoundAs(ErrUnknownArea, area, err)
 	}
+	pokemons = []string{}
 	for _, entity := range pokemonEntities {
 		pokemons = append(pokemons, entity.Name)
 	}
-	return pokemons, available
+	return pokemons, nil
 }
 
-func fetchPokemons(url string) (areas []PokemonEntity, err error) {
-	data, available := cache.Get(url)
-	if !available {
-		data, err = fetchData(url)
-		if err != nil {
-			return []PokemonEntity{}, err
-		}
-	}
-	cache.Add(url, data)
-	var locationData LocationInfo
-	err = json.Unmarshal(data, &locationData)
+func (client *Client) fetchPokemons(ctx context.Context, area string) (areas []PokemonEntity, err error) {
+	locationData, err := client.fetchLocationInfo(ctx, area)
 	if err != nil {
-		return []PokemonEntity{}, fmt.Errorf("Error decoding data received from %s: %w", url, err)
+		return []PokemonEntity{}, err
 	}
 	pokemons := []PokemonEntity{}
 	for _, pokemon := range locationData.PokemonEncounters {
@@ -105,59 +68,18 @@ func fetchPokemons(url string) (areas []PokemonEntity, err error) {
 	return pokemons, nil
 }
 
-func CatchPokemon(name string) (caught bool, err error) {
-	pokemon, err := fetchPokemon(name)
-	if err != nil {
-		fmt.Println(err)
-		return caught, err
-	}
-	random := rand.IntN(1000)
-	if random > pokemon.BaseExperience {
-		caught = true
-		caughtPokemon[pokemon.Name] = pokemon
-	}
-	return caught, nil
+func (client *Client) fetchPokemon(ctx context.Context, name string) (pokemon Pokemon, err error) {
+	return fetchDecoded(ctx, client, client.decoded.pokemon, client.endpoint("pokemon/"+name))
 }
 
-func fetchPokemon(name string) (pokemon Pokemon, err error) {
-	url := "https://pokeapi.co/api/v2/pokemon/" + name
-	data, available := cache.Get(url)
-	if !available {
-		data, err = fetchData(url)
-		if err != nil {
-			return pokemon, err
-		}
-	}
-	cache.Add(url, data)
-
-	err = json.Unmarshal(data, &pokemon)
-	if err != nil {
-		return pokemon, fmt.Errorf("Error unmarshalling body from %s: %w", url, err)
-	}
-	return pokemon, nil
+// Inspect returns the caught Pokémon reference refers to: an ID, a nickname
+// or a Pokémon name only one of them has. It fails with ErrNotCaught or
+// ErrAmbiguous.
+func Inspect(reference string) (caught CaughtPokemon, err error) {
+	return findCaught(reference)
 }
 
-func Inspect(name string) {
-	if pokemon, caught := caughtPokemon[name]; caught {
-		fmt.Println("\tName:", pokemon.Name)
-		fmt.Println("\tHeight:", pokemon.Height)
-		fmt.Println("\tWeight:", pokemon.Weight)
-		fmt.Println("\tStats:")
-		for _, stat := range pokemon.Stats {
-			fmt.Printf("\t\t-%s: %d\n", stat.Stat.Name, stat.BaseStat)
-		}
-		fmt.Println("\tTypes:")
-		for _, poketype := range pokemon.Types {
-			fmt.Printf("\t\t-%s\n", poketype.Type.Name)
-		}
-		return
-	}
-	fmt.Println("\tyou have not caught that pokemon")
-}
-
-func ViewPokedex() {
-	fmt.Println("\tYour Pokedex:")
-	for key, _ := range caughtPokemon {
-		fmt.Println("\t\t-", key)
-	}
+// ViewPokedex returns every caught Pokémon in the order they were caught.
+func ViewPokedex() (collection []CaughtPokemon) {
+	return sortedCaught(caughtPokemon)
 }
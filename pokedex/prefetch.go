@@ -0,0 +1,54 @@
+package pokedex
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultPrefetchWorkers bounds how many Pokémon PrefetchPokemon fetches at once.
+const DefaultPrefetchWorkers = 4
+
+// PrefetchPokemon fetches the named Pokémon into the cache, workers at a time,
+// so a later catch or inspect finds them already decoded. It is best effort:
+// failures are skipped and it stops early once ctx is done. It returns how
+// many were fetched.
+func (client *Client) PrefetchPokemon(ctx context.Context, names []string, workers int) (fetched int) {
+	if workers <= 0 {
+		workers = DefaultPrefetchWorkers
+	}
+	var count atomic.Int32
+	forEach(ctx, names, workers, func(name string) {
+		if _, err := client.fetchPokemon(ctx, name); err == nil {
+			count.Add(1)
+		}
+	})
+	return int(count.Load())
+}
+
+// forEach calls work for each of items on up to workers goroutines, or one
+// when workers is not positive, and returns once they are done. Items not yet
+// handed out when ctx is done are skipped.
+func forEach(ctx context.Context, items []string, workers int, work func(item string)) {
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range min(max(workers, 1), len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				work(item)
+			}
+		}()
+	}
+feed:
+	for _, item := range items {
+		select {
+		case jobs <- item:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+package pokedex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pokecache "github.com/anantashahane/pokedex/pokecache"
+)
+
+// newPrefetchServer serves tentacool for every /pokemon/ request, slowly
+// enough for concurrent requests to overlap, and records how many overlapped.
+func newPrefetchServer(t *testing.T) (client *Client, requests, peak *atomic.Int32) {
+	t.Helper()
+	requests, peak = &atomic.Int32{}, &atomic.Int32{}
+	active := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		current := active.Add(1)
+		defer active.Add(-1)
+		for {
+			highest := peak.Load()
+			if current <= highest || peak.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(fixtures["/api/v2/pokemon/tentacool"]))
+	}))
+	t.Cleanup(server.Close)
+	cache := pokecache.NewCache(time.Minute)
+	t.Cleanup(cache.Close)
+	return NewClient(server.URL, server.Client(), cache), requests, peak
+}
+
+func TestPrefetchPokemonIsBounded(t *testing.T) {
+	client, requests, peak := newPrefetchServer(t)
+	names := []string{}
+	for i := range 8 {
+		names = append(names, fmt.Sprintf("pokemon-%d", i))
+	}
+
+	fetched := client.PrefetchPokemon(context.Background(), names, 2)
+	if fetched != len(names) || requests.Load() != int32(len(names)) {
+		t.Errorf("got %d fetched with %d requests, want %d of each", fetched, requests.Load(), len(names))
+	}
+	if peak.Load() > 2 {
+		t.Errorf("got %d concurrent requests, want at most 2", peak.Load())
+	}
+
+	_, err := client.fetchPokemon(context.Background(), names[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != int32(len(names)) {
+		t.Errorf("Expected a prefetched pokemon to be served from the cache")
+	}
+}
+
+func TestPrefetchPokemonStopsWhenCancelled(t *testing.T) {
+	client, _, _ := newPrefetchServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if fetched := client.PrefetchPokemon(ctx, []string{"tentacool", "vaporeon"}, 2); fetched != 0 {
+		t.Errorf("got %d fetched after cancellation, want 0", fetched)
+	}
+}
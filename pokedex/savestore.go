@@ -0,0 +1,218 @@
+package pokedex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// saveFileVersion 3 stores a list of individual CaughtPokemon. Version 2 kept
+// one CaughtPokemon per name and version 1 one bare Pokemon per name.
+const saveFileVersion = 3
+
+const (
+	// migratedHappiness is given to Pokémon from version 1 saves, which did
+	// not record friendship. It is the base friendship of most species.
+	migratedHappiness = 70
+	// migratedNature is the neutral nature given to Pokémon from saves older
+	// than version 3.
+	migratedNature = "hardy"
+)
+
+// Collection is everything a trainer has caught, as kept by a SaveStore.
+type Collection struct {
+	Pokemon []CaughtPokemon `json:"pokemon"`
+	// NextID is the ID the next catch gets, so that IDs are never reused.
+	NextID int `json:"next_id"`
+	// Party and Boxes hold IDs from Pokemon. A Pokémon in neither is placed
+	// like a new catch when loaded.
+	Party []int   `json:"party"`
+	Boxes [][]int `json:"boxes"`
+}
+
+// SaveStore persists a trainer's caught Pokémon between sessions.
+type SaveStore interface {
+	Save(collection Collection) error
+	Load() (collection Collection, err error)
+}
+
+// JSONFileStore is a SaveStore that keeps the collection in a single JSON file.
+type JSONFileStore struct {
+	path string
+}
+
+type saveFile struct {
+	Version int             `json:"version"`
+	NextID  int             `json:"next_id"`
+	Pokemon json.RawMessage `json:"pokemon"`
+	Party   []int           `json:"party"`
+	Boxes   [][]int         `json:"boxes"`
+}
+
+func NewJSONFileStore(path string) *JSONFileStore {
+	return &JSONFileStore{path: path}
+}
+
+// DefaultSavePath returns the save file location under the user's config directory.
+func DefaultSavePath() (path string, err error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("Error locating config directory: %w", err)
+	}
+	return filepath.Join(dir, "pokedex", "save.json"), nil
+}
+
+func (store *JSONFileStore) Save(collection Collection) error {
+	pokemon, err := json.Marshal(collection.Pokemon)
+	if err != nil {
+		return fmt.Errorf("Error encoding save data: %w", err)
+	}
+	data, err := json.Marshal(saveFile{
+		Version: saveFileVersion,
+		NextID:  collection.NextID,
+		Pokemon: pokemon,
+		Party:   collection.Party,
+		Boxes:   collection.Boxes,
+	})
+	if err != nil {
+		return fmt.Errorf("Error encoding save data: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(store.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("Error creating save directory for %s: %w", store.path, err)
+	}
+	// Write to a temporary file first so a crash mid-write never truncates an
+	// existing save. Each save gets its own, so concurrent saves cannot
+	// interleave into one file.
+	tmp, err := os.CreateTemp(filepath.Dir(store.path), filepath.Base(store.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("Error creating temporary save file for %s: %w", store.path, err)
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("Error writing save file %s: %w", tmp.Name(), err)
+	}
+	err = os.Rename(tmp.Name(), store.path)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("Error replacing save file %s: %w", store.path, err)
+	}
+	return nil
+}
+
+// Load returns an empty collection when no save file exists yet.
+func (store *JSONFileStore) Load() (collection Collection, err error) {
+	collection = Collection{Pokemon: []CaughtPokemon{}, NextID: 1}
+	data, err := os.ReadFile(store.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return collection, nil
+	}
+	if err != nil {
+		return collection, fmt.Errorf("Error reading save file %s: %w", store.path, err)
+	}
+	var save saveFile
+	err = json.Unmarshal(data, &save)
+	if err != nil {
+		return collection, fmt.Errorf("Error decoding save file %s: %w", store.path, err)
+	}
+	if save.Version > saveFileVersion {
+		return collection, fmt.Errorf("Save file %s has unsupported version %d", store.path, save.Version)
+	}
+	if len(save.Pokemon) == 0 || string(save.Pokemon) == "null" {
+		return collection, nil
+	}
+	switch save.Version {
+	case 0, 1:
+		err = migrateVersion1(save.Pokemon, &collection)
+	case 2:
+		err = migrateVersion2(save.Pokemon, &collection)
+	default:
+		err = json.Unmarshal(save.Pokemon, &collection.Pokemon)
+		collection.NextID = max(save.NextID, 1)
+		collection.Party, collection.Boxes = save.Party, save.Boxes
+	}
+	if err != nil {
+		return collection, fmt.Errorf("Error decoding save file %s: %w", store.path, err)
+	}
+	return collection, nil
+}
+
+// migrateVersion1 upgrades bare Pokemon to CaughtPokemon at DefaultCatchLevel.
+func migrateVersion1(data json.RawMessage, collection *Collection) error {
+	legacy := map[string]Pokemon{}
+	err := json.Unmarshal(data, &legacy)
+	if err != nil {
+		return err
+	}
+	byName := map[string]CaughtPokemon{}
+	for name, pokemon := range legacy {
+		byName[name] = CaughtPokemon{
+			Pokemon:    pokemon,
+			Level:      DefaultCatchLevel,
+			Experience: experienceForLevel("", DefaultCatchLevel),
+			Happiness:  migratedHappiness,
+		}
+	}
+	assignIDs(byName, collection)
+	return nil
+}
+
+// migrateVersion2 gives each Pokémon kept by name its own ID.
+func migrateVersion2(data json.RawMessage, collection *Collection) error {
+	byName := map[string]CaughtPokemon{}
+	err := json.Unmarshal(data, &byName)
+	if err != nil {
+		return err
+	}
+	assignIDs(byName, collection)
+	return nil
+}
+
+// assignIDs numbers byName alphabetically into collection.
+func assignIDs(byName map[string]CaughtPokemon, collection *Collection) {
+	names := []string{}
+	for name := range byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		caught := byName[name]
+		caught.ID = collection.NextID
+		caught.Nature = migratedNature
+		collection.NextID++
+		collection.Pokemon = append(collection.Pokemon, caught)
+	}
+}
+
+// SavePokedex writes the current collection to store.
+func SavePokedex(store SaveStore) error {
+	return store.Save(Collection{Pokemon: ViewPokedex(), NextID: nextID, Party: party, Boxes: boxes})
+}
+
+// LoadPokedex replaces the current collection, party and boxes with the ones
+// held in store.
+func LoadPokedex(store SaveStore) error {
+	collection, err := store.Load()
+	if err != nil {
+		return err
+	}
+	caughtPokemon = map[int]CaughtPokemon{}
+	nextID = max(collection.NextID, 1)
+	for _, caught := range collection.Pokemon {
+		caughtPokemon[caught.ID] = caught
+		nextID = max(nextID, caught.ID+1)
+	}
+	arrange(collection.Party, collection.Boxes)
+	return nil
+}
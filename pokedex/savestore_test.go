@@ -0,0 +1,95 @@
+package pokedex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestJSONFileStoreRoundTrip(t *testing.T) {
+	store := NewJSONFileStore(filepath.Join(t.TempDir(), "nested", "save.json"))
+
+	collection, err := store.Load()
+	if err != nil {
+		t.Fatalf("Loading a missing save file failed: %v", err)
+	}
+	if len(collection.Pokemon) != 0 || collection.NextID != 1 {
+		t.Errorf("Expected empty collection, got %+v", collection)
+	}
+
+	pikachu := CaughtPokemon{ID: 3, Nickname: "Sparky", Pokemon: Pokemon{Name: "pikachu", Height: 4, Weight: 60}, Level: 12, HeldItem: "light-ball"}
+	err = store.Save(Collection{Pokemon: []CaughtPokemon{pikachu}, NextID: 5})
+	if err != nil {
+		t.Fatalf("Saving failed: %v", err)
+	}
+
+	collection, err = store.Load()
+	if err != nil {
+		t.Fatalf("Loading failed: %v", err)
+	}
+	if len(collection.Pokemon) != 1 || collection.NextID != 5 {
+		t.Fatalf("Expected pikachu and next ID 5, got %+v", collection)
+	}
+	loaded := collection.Pokemon[0]
+	if loaded.ID != 3 || loaded.Nickname != "Sparky" || loaded.Pokemon.Height != pikachu.Pokemon.Height || loaded.Level != pikachu.Level || loaded.HeldItem != pikachu.HeldItem {
+		t.Errorf("Expected %+v, got %+v", pikachu, loaded)
+	}
+}
+
+func TestJSONFileStoreRejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	err := os.WriteFile(path, []byte("{not json"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewJSONFileStore(path).Load()
+	if err == nil {
+		t.Errorf("Expected an error decoding a corrupt save file")
+	}
+}
+
+func TestJSONFileStoreConcurrentSaves(t *testing.T) {
+	dir := t.TempDir()
+	store := NewJSONFileStore(filepath.Join(dir, "save.json"))
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pokemon := []CaughtPokemon{{ID: i + 1, Pokemon: Pokemon{Name: strings.Repeat("tentacool", 1000)}}}
+			if err := store.Save(Collection{Pokemon: pokemon, NextID: i + 2}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	collection, err := store.Load()
+	if err != nil || len(collection.Pokemon) != 1 {
+		t.Fatalf("Expected one whole save to win, got %+v, %v", collection, err)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil || len(files) != 1 {
+		t.Errorf("Expected only the save file to be left, got %v, %v", files, err)
+	}
+}
+
+func TestJSONFileStoreMigratesVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	err := os.WriteFile(path, []byte(`{"version": 1, "pokemon": {"pikachu": {"name": "pikachu", "height": 4}, "eevee": {"name": "eevee"}}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection, err := NewJSONFileStore(path).Load()
+	if err != nil {
+		t.Fatalf("Loading a version 1 save failed: %v", err)
+	}
+	if len(collection.Pokemon) != 2 || collection.NextID != 3 {
+		t.Fatalf("Expected two migrated pokemon, got %+v", collection)
+	}
+	pikachu := collection.Pokemon[1]
+	if pikachu.ID != 2 || pikachu.Pokemon.Height != 4 || pikachu.Level != DefaultCatchLevel || pikachu.Experience != 125 {
+		t.Errorf("Unexpected migrated entry %+v", pikachu)
+	}
+}
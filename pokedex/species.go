@@ -0,0 +1,168 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownSpecies = errors.New("unknown species")
+
+// GetSpecies looks name up as a species, falling back to following the
+// species link of a Pokémon with that name, e.g. for forms like deoxys-attack.
+func (client *Client) GetSpecies(ctx context.Context, name string) (species SpeciesInfo, err error) {
+	species, err = client.fetchSpecies(ctx, name)
+	if !errors.Is(err, ErrNotFound) {
+		return species, err
+	}
+	pokemon, pokemonErr := client.fetchPokemon(ctx, name)
+	if pokemonErr != nil {
+		return species, notFoundAs(ErrUnknownSpecies, name, pokemonErr)
+	}
+	return client.fetchSpecies(ctx, pokemon.Species.Name)
+}
+
+// EnglishFlavorText returns the most recent English Pokédex entry with its
+// hard line breaks removed.
+func (species SpeciesInfo) EnglishFlavorText() string {
+	return englishFlavorText(species.FlavorTextEntries)
+}
+
+// EnglishGenus returns the species category, e.g. "Mouse Pokémon".
+func (species SpeciesInfo) EnglishGenus() string {
+	for _, genus := range species.Genera {
+		if genus.Language.Name == "en" {
+			return genus.Genus
+		}
+	}
+	return ""
+}
+
+// EvolutionNode is one stage of an evolution tree. Conditions describe how
+// the previous stage evolves into this one and are empty for the root.
+type EvolutionNode struct {
+	Species    string            `json:"species"`
+	Conditions []EvolutionDetail `json:"conditions"`
+	EvolvesTo  []EvolutionNode   `json:"evolves_to"`
+}
+
+// GetEvolutionChain returns the full evolution tree that the named species, or
+// the species of the named Pokémon, belongs to.
+func (client *Client) GetEvolutionChain(ctx context.Context, name string) (root EvolutionNode, err error) {
+	species, err := client.GetSpecies(ctx, name)
+	if err != nil {
+		return root, err
+	}
+	chain, err := client.fetchEvolutionChain(ctx, species.EvolutionChain.URL)
+	if err != nil {
+		return root, err
+	}
+	return newEvolutionNode(chain.Chain), nil
+}
+
+func newEvolutionNode(link ChainLink) EvolutionNode {
+	node := EvolutionNode{Species: link.Species.Name, Conditions: link.EvolutionDetails, EvolvesTo: []EvolutionNode{}}
+	if node.Conditions == nil {
+		node.Conditions = []EvolutionDetail{}
+	}
+	for _, next := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, newEvolutionNode(next))
+	}
+	return node
+}
+
+// Find returns the node for species within the tree rooted at node.
+func (node EvolutionNode) Find(species string) (found EvolutionNode, exists bool) {
+	if node.Species == species {
+		return node, true
+	}
+	for _, next := range node.EvolvesTo {
+		if found, exists = next.Find(species); exists {
+			return found, true
+		}
+	}
+	return EvolutionNode{}, false
+}
+
+// String describes the condition in words, e.g. "level 16" or "use thunder-stone".
+func (detail EvolutionDetail) String() string {
+	parts := []string{}
+	switch detail.Trigger.Name {
+	case "level-up":
+		if detail.MinLevel > 0 {
+			parts = append(parts, fmt.Sprintf("level %d", detail.MinLevel))
+		} else {
+			parts = append(parts, "level up")
+		}
+	case "use-item":
+		if detail.Item != nil {
+			parts = append(parts, "use "+detail.Item.Name)
+		}
+	case "trade":
+		parts = append(parts, "trade")
+	default:
+		parts = append(parts, strings.ReplaceAll(detail.Trigger.Name, "-", " "))
+	}
+	if detail.HeldItem != nil {
+		parts = append(parts, "holding "+detail.HeldItem.Name)
+	}
+	if detail.TradeSpecies != nil {
+		parts = append(parts, "for "+detail.TradeSpecies.Name)
+	}
+	if detail.MinHappiness > 0 {
+		parts = append(parts, fmt.Sprintf("with friendship %d", detail.MinHappiness))
+	}
+	if detail.MinAffection > 0 {
+		parts = append(parts, fmt.Sprintf("with affection %d", detail.MinAffection))
+	}
+	if detail.MinBeauty > 0 {
+		parts = append(parts, fmt.Sprintf("with beauty %d", detail.MinBeauty))
+	}
+	if detail.KnownMove != nil {
+		parts = append(parts, "knowing "+detail.KnownMove.Name)
+	}
+	if detail.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+detail.KnownMoveType.Name+" move")
+	}
+	if detail.Location != nil {
+		parts = append(parts, "at "+detail.Location.Name)
+	}
+	if detail.TimeOfDay != "" {
+		parts = append(parts, "during the "+detail.TimeOfDay)
+	}
+	if detail.PartySpecies != nil {
+		parts = append(parts, "with "+detail.PartySpecies.Name+" in the party")
+	}
+	if detail.PartyType != nil {
+		parts = append(parts, "with a "+detail.PartyType.Name+" type in the party")
+	}
+	switch detail.Gender {
+	case 1:
+		parts = append(parts, "if female")
+	case 2:
+		parts = append(parts, "if male")
+	}
+	// Zero means both "attack equals defense" and null, so it is not shown.
+	switch detail.RelativePhysicalStats {
+	case 1:
+		parts = append(parts, "if attack > defense")
+	case -1:
+		parts = append(parts, "if attack < defense")
+	}
+	if detail.NeedsOverworldRain {
+		parts = append(parts, "while raining")
+	}
+	if detail.TurnUpsideDown {
+		parts = append(parts, "with the console upside down")
+	}
+	return strings.Join(parts, " ")
+}
+
+func (client *Client) fetchSpecies(ctx context.Context, name string) (species SpeciesInfo, err error) {
+	return fetchDecoded(ctx, client, client.decoded.species, client.endpoint("pokemon-species/"+name))
+}
+
+func (client *Client) fetchEvolutionChain(ctx context.Context, apiURL string) (chain EvolutionChainInfo, err error) {
+	return fetchDecoded(ctx, client, client.decoded.evolutionChain, client.resolve(apiURL))
+}
@@ -0,0 +1,50 @@
+package pokedex
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetSpecies(t *testing.T) {
+	client, _ := newTestClient(t)
+	species, err := client.GetSpecies(context.Background(), "eevee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if species.EnglishGenus() != "Evolution Pokémon" {
+		t.Errorf("Unexpected genus %q", species.EnglishGenus())
+	}
+	if species.EnglishFlavorText() != "Its genetic code is irregular." {
+		t.Errorf("Unexpected flavor text %q", species.EnglishFlavorText())
+	}
+
+	_, err = client.GetSpecies(context.Background(), "missingno")
+	if !errors.Is(err, ErrUnknownSpecies) {
+		t.Errorf("Expected ErrUnknownSpecies, got %v", err)
+	}
+}
+
+func TestGetEvolutionChain(t *testing.T) {
+	client, _ := newTestClient(t)
+	root, err := client.GetEvolutionChain(context.Background(), "eevee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Species != "eevee" || len(root.EvolvesTo) != 2 {
+		t.Fatalf("Expected eevee with two branches, got %+v", root)
+	}
+	cases := map[string]string{
+		"vaporeon": "use water-stone",
+		"espeon":   "level up with friendship 160 during the day",
+	}
+	for species, expected := range cases {
+		node, exists := root.Find(species)
+		if !exists {
+			t.Fatalf("Expected %s in the tree", species)
+		}
+		if actual := node.Conditions[0].String(); actual != expected {
+			t.Errorf("Expected %s condition %q, got %q", species, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package pokedex
+
+import (
+	battle "github.com/anantashahane/pokedex/battle"
+)
+
+const (
+	maxIV = 31
+	// maxStatEffort and maxTotalEffort cap the effort values of one stat and of all six.
+	maxStatEffort  = 252
+	maxTotalEffort = 510
+)
+
+// StatNames lists the six stats in the order the API returns them.
+var StatNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// Stats holds one value per stat, e.g. individual or effort values.
+type Stats struct {
+	HP             int `json:"hp"`
+	Attack         int `json:"attack"`
+	Defense        int `json:"defense"`
+	SpecialAttack  int `json:"special-attack"`
+	SpecialDefense int `json:"special-defense"`
+	Speed          int `json:"speed"`
+}
+
+// Get returns the value of the stat called name, or zero for an unknown name.
+func (stats Stats) Get(name string) int {
+	switch name {
+	case "hp":
+		return stats.HP
+	case "attack":
+		return stats.Attack
+	case "defense":
+		return stats.Defense
+	case "special-attack":
+		return stats.SpecialAttack
+	case "special-defense":
+		return stats.SpecialDefense
+	case "speed":
+		return stats.Speed
+	}
+	return 0
+}
+
+func (stats *Stats) set(name string, value int) {
+	switch name {
+	case "hp":
+		stats.HP = value
+	case "attack":
+		stats.Attack = value
+	case "defense":
+		stats.Defense = value
+	case "special-attack":
+		stats.SpecialAttack = value
+	case "special-defense":
+		stats.SpecialDefense = value
+	case "speed":
+		stats.Speed = value
+	}
+}
+
+func (stats Stats) total() (sum int) {
+	for _, name := range StatNames {
+		sum += stats.Get(name)
+	}
+	return sum
+}
+
+// Nature raises one stat by 10% and lowers another by 10%. Natures that
+// raise and lower the same stat have no effect.
+type Nature struct {
+	Name      string
+	Increased string
+	Decreased string
+}
+
+// Natures lists all 25 natures in their index order.
+var Natures = []Nature{
+	{"hardy", "attack", "attack"},
+	{"lonely", "attack", "defense"},
+	{"brave", "attack", "speed"},
+	{"adamant", "attack", "special-attack"},
+	{"naughty", "attack", "special-defense"},
+	{"bold", "defense", "attack"},
+	{"docile", "defense", "defense"},
+	{"relaxed", "defense", "speed"},
+	{"impish", "defense", "special-attack"},
+	{"lax", "defense", "special-defense"},
+	{"timid", "speed", "attack"},
+	{"hasty", "speed", "defense"},
+	{"serious", "speed", "speed"},
+	{"jolly", "speed", "special-attack"},
+	{"naive", "speed", "special-defense"},
+	{"modest", "special-attack", "attack"},
+	{"mild", "special-attack", "defense"},
+	{"quiet", "special-attack", "speed"},
+	{"bashful", "special-attack", "special-attack"},
+	{"rash", "special-attack", "special-defense"},
+	{"calm", "special-defense", "attack"},
+	{"gentle", "special-defense", "defense"},
+	{"sassy", "special-defense", "speed"},
+	{"careful", "special-defense", "special-attack"},
+	{"quirky", "special-defense", "special-defense"},
+}
+
+// natureModifier scales value by the nature called nature for the stat called
+// stat. Unknown natures are neutral.
+func natureModifier(nature, stat string, value int) int {
+	for _, candidate := range Natures {
+		if candidate.Name != nature || candidate.Increased == candidate.Decreased {
+			continue
+		}
+		switch stat {
+		case candidate.Increased:
+			return value * 11 / 10
+		case candidate.Decreased:
+			return value * 9 / 10
+		}
+	}
+	return value
+}
+
+// baseStats returns the species' base stats of pokemon.
+func baseStats(pokemon Pokemon) (stats Stats) {
+	for _, stat := range pokemon.Stats {
+		stats.set(stat.Stat.Name, stat.BaseStat)
+	}
+	return stats
+}
+
+// CalculatedStats returns the actual stats of caught from its base stats,
+// level, individual and effort values and nature.
+func (caught CaughtPokemon) CalculatedStats() (stats Stats) {
+	base := baseStats(caught.Pokemon)
+	for _, name := range StatNames {
+		raw := (2*base.Get(name) + caught.IVs.Get(name) + caught.EVs.Get(name)/4) * caught.Level / 100
+		if name == "hp" {
+			stats.set(name, raw+caught.Level+10)
+			continue
+		}
+		stats.set(name, natureModifier(caught.Nature, name, raw+5))
+	}
+	return stats
+}
+
+// battleStats folds individual and effort values and nature into base stats,
+// since the battle engine scales base stats by level itself.
+func (caught CaughtPokemon) battleStats() battle.Stats {
+	base := baseStats(caught.Pokemon)
+	effective := Stats{}
+	for _, name := range StatNames {
+		value := base.Get(name) + (caught.IVs.Get(name)+caught.EVs.Get(name)/4)/2
+		if name != "hp" {
+			value = natureModifier(caught.Nature, name, value)
+		}
+		effective.set(name, value)
+	}
+	return battle.Stats{
+		HP:             effective.HP,
+		Attack:         effective.Attack,
+		Defense:        effective.Defense,
+		SpecialAttack:  effective.SpecialAttack,
+		SpecialDefense: effective.SpecialDefense,
+		Speed:          effective.Speed,
+	}
+}
+
+// gainEffort adds the effort values defeating opponent yields, within the
+// per-stat and total caps.
+func (caught *CaughtPokemon) gainEffort(opponent Pokemon) {
+	for _, stat := range opponent.Stats {
+		room := min(maxStatEffort-caught.EVs.Get(stat.Stat.Name), maxTotalEffort-caught.EVs.total())
+		gain := min(stat.Effort, max(room, 0))
+		caught.EVs.set(stat.Stat.Name, caught.EVs.Get(stat.Stat.Name)+gain)
+	}
+}
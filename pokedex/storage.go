@@ -0,0 +1,246 @@
+package pokedex
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	// PartySize is how many Pokémon the trainer carries.
+	PartySize = 6
+	// BoxCount and BoxSize describe the PC boxes everything else is kept in.
+	BoxCount = 8
+	BoxSize  = 30
+)
+
+var (
+	ErrPartyFull    = errors.New("your party is full")
+	ErrBoxFull      = errors.New("that box is full")
+	ErrStorageFull  = errors.New("your party and every box are full")
+	ErrUnknownBox   = errors.New("unknown box")
+	ErrLastInParty  = errors.New("your party must keep at least one pokemon")
+	ErrAlreadyParty = errors.New("that pokemon is already in your party")
+)
+
+// party and boxes hold the IDs of caught Pokémon. Every caught Pokémon is in
+// exactly one of them. Boxes are numbered from 1, and box 0 stands for the party.
+var (
+	party = []int{}
+	boxes = newBoxes()
+)
+
+func newBoxes() [][]int {
+	boxes := make([][]int, BoxCount)
+	for i := range boxes {
+		boxes[i] = []int{}
+	}
+	return boxes
+}
+
+// container returns the IDs held by box, where box 0 is the party.
+func container(box int) *[]int {
+	if box == 0 {
+		return &party
+	}
+	return &boxes[box-1]
+}
+
+func capacity(box int) int {
+	if box == 0 {
+		return PartySize
+	}
+	return BoxSize
+}
+
+// place puts id into the party if it has room, otherwise into the first box
+// with room, and returns where it went.
+func place(id int) (box int, err error) {
+	for box = 0; box <= BoxCount; box++ {
+		held := container(box)
+		if len(*held) < capacity(box) {
+			*held = append(*held, id)
+			return box, nil
+		}
+	}
+	return 0, ErrStorageFull
+}
+
+// firstFreeBox returns the first box with room, or 0 when all are full.
+func firstFreeBox() int {
+	for box := 1; box <= BoxCount; box++ {
+		if len(*container(box)) < BoxSize {
+			return box
+		}
+	}
+	return 0
+}
+
+func storageFull() bool {
+	for box := 0; box <= BoxCount; box++ {
+		if len(*container(box)) < capacity(box) {
+			return false
+		}
+	}
+	return true
+}
+
+// locate returns the box holding id and its slot there, or -1 for both.
+func locate(id int) (box, slot int) {
+	for box = 0; box <= BoxCount; box++ {
+		if slot = slices.Index(*container(box), id); slot >= 0 {
+			return box, slot
+		}
+	}
+	return -1, -1
+}
+
+// remove takes id out of its box, refusing to empty the party.
+func remove(id int) error {
+	box, slot := locate(id)
+	if box < 0 {
+		return ErrNotCaught
+	}
+	if box == 0 && len(party) == 1 {
+		return ErrLastInParty
+	}
+	held := container(box)
+	*held = slices.Delete(*held, slot, slot+1)
+	return nil
+}
+
+// BoxOf returns the box holding the caught Pokémon id, 0 for the party, or -1.
+func BoxOf(id int) int {
+	box, _ := locate(id)
+	return box
+}
+
+// Party returns the Pokémon in the party in order.
+func Party() []CaughtPokemon {
+	return BoxContents(0)
+}
+
+// BoxContents returns the Pokémon in box in order, where box 0 is the party.
+func BoxContents(box int) (contents []CaughtPokemon) {
+	contents = []CaughtPokemon{}
+	if box < 0 || box > BoxCount {
+		return contents
+	}
+	for _, id := range *container(box) {
+		contents = append(contents, caughtPokemon[id])
+	}
+	return contents
+}
+
+// Deposit moves the caught Pokémon reference into box, or into the first box
+// with room when box is zero, and returns it with the box it is now in. A
+// Pokémon already in that box, or in any box when box is zero, stays put.
+func Deposit(reference string, box int) (deposited CaughtPokemon, to int, err error) {
+	if box < 0 || box > BoxCount {
+		return deposited, 0, fmt.Errorf("%w %d, boxes are numbered 1 to %d", ErrUnknownBox, box, BoxCount)
+	}
+	deposited, err = findCaught(reference)
+	if err != nil {
+		return deposited, 0, err
+	}
+	current := BoxOf(deposited.ID)
+	if current > 0 && (box == 0 || current == box) {
+		return deposited, current, nil
+	}
+	if box == 0 {
+		box = firstFreeBox()
+		if box == 0 {
+			return deposited, 0, ErrStorageFull
+		}
+	}
+	if len(*container(box)) >= BoxSize {
+		return deposited, 0, fmt.Errorf("%w: box %d", ErrBoxFull, box)
+	}
+	err = remove(deposited.ID)
+	if err != nil {
+		return deposited, 0, err
+	}
+	held := container(box)
+	*held = append(*held, deposited.ID)
+	return deposited, box, nil
+}
+
+// Withdraw moves the caught Pokémon reference from its box into the party and
+// returns it.
+func Withdraw(reference string) (withdrawn CaughtPokemon, err error) {
+	withdrawn, err = findCaught(reference)
+	if err != nil {
+		return withdrawn, err
+	}
+	if BoxOf(withdrawn.ID) == 0 {
+		return withdrawn, ErrAlreadyParty
+	}
+	if len(party) >= PartySize {
+		return withdrawn, ErrPartyFull
+	}
+	err = remove(withdrawn.ID)
+	if err != nil {
+		return withdrawn, err
+	}
+	party = append(party, withdrawn.ID)
+	return withdrawn, nil
+}
+
+// Swap exchanges the places of two caught Pokémon, e.g. to change the party
+// lead or to trade a party member for one in a box, and returns both.
+func Swap(first, second string) (a, b CaughtPokemon, err error) {
+	a, err = findCaught(first)
+	if err != nil {
+		return a, b, err
+	}
+	b, err = findCaught(second)
+	if err != nil {
+		return a, b, err
+	}
+	boxA, slotA := locate(a.ID)
+	boxB, slotB := locate(b.ID)
+	(*container(boxA))[slotA], (*container(boxB))[slotB] = b.ID, a.ID
+	return a, b, nil
+}
+
+// Release removes the caught Pokémon reference from the collection for good.
+func Release(reference string) (released CaughtPokemon, err error) {
+	released, err = findCaught(reference)
+	if err != nil {
+		return released, err
+	}
+	err = remove(released.ID)
+	if err != nil {
+		return released, err
+	}
+	delete(caughtPokemon, released.ID)
+	return released, nil
+}
+
+// arrange rebuilds the party and boxes from saved ones, dropping IDs that are
+// not in the collection and placing Pokémon that are in neither, as in saves
+// made before the party existed.
+func arrange(savedParty []int, savedBoxes [][]int) {
+	party, boxes = []int{}, newBoxes()
+	seen := map[int]bool{}
+	keep := func(box int, ids []int) {
+		held := container(box)
+		for _, id := range ids {
+			if _, exists := caughtPokemon[id]; exists && !seen[id] && len(*held) < capacity(box) {
+				*held = append(*held, id)
+				seen[id] = true
+			}
+		}
+	}
+	keep(0, savedParty)
+	for i, ids := range savedBoxes {
+		if i < BoxCount {
+			keep(i+1, ids)
+		}
+	}
+	for _, caught := range sortedCaught(caughtPokemon) {
+		if !seen[caught.ID] {
+			place(caught.ID)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package pokedex
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// fillCollection catches count pokemon named pokemon1, pokemon2 and so on.
+func fillCollection(t *testing.T, count int) {
+	t.Helper()
+	for i := 1; i <= count; i++ {
+		_, _, err := addCaught(CaughtPokemon{Pokemon: Pokemon{Name: "pokemon" + strconv.Itoa(i)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCatchesFillPartyThenBoxes(t *testing.T) {
+	resetCollection(t)
+	fillCollection(t, PartySize)
+	_, box, err := addCaught(CaughtPokemon{Pokemon: Pokemon{Name: "overflow"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if box != 1 || len(Party()) != PartySize || len(BoxContents(1)) != 1 {
+		t.Errorf("Expected the seventh catch in box 1, got box %d", box)
+	}
+
+	for i := 0; i < BoxCount*BoxSize-1; i++ {
+		_, _, err = addCaught(CaughtPokemon{Pokemon: Pokemon{Name: "filler"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, _, err = addCaught(CaughtPokemon{Pokemon: Pokemon{Name: "one-too-many"}})
+	if !errors.Is(err, ErrStorageFull) {
+		t.Errorf("Expected ErrStorageFull, got %v", err)
+	}
+}
+
+func TestDepositWithdrawSwapRelease(t *testing.T) {
+	resetCollection(t)
+	fillCollection(t, 2)
+
+	deposited, box, err := Deposit("pokemon1", 0)
+	if err != nil || box != 1 || deposited.ID != 1 {
+		t.Fatalf("Expected #1 to be deposited into box 1, got #%d in %d, %v", deposited.ID, box, err)
+	}
+	if _, _, err := Deposit("pokemon2", 0); !errors.Is(err, ErrLastInParty) {
+		t.Errorf("Expected ErrLastInParty, got %v", err)
+	}
+	if _, _, err := Deposit("pokemon2", BoxCount+1); !errors.Is(err, ErrUnknownBox) {
+		t.Errorf("Expected ErrUnknownBox, got %v", err)
+	}
+
+	_, _, err = Swap("pokemon1", "pokemon2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if BoxOf(1) != 0 || BoxOf(2) != 1 {
+		t.Errorf("Expected swap to exchange places, got %d and %d", BoxOf(1), BoxOf(2))
+	}
+
+	_, err = Withdraw("pokemon2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Withdraw("pokemon2"); !errors.Is(err, ErrAlreadyParty) {
+		t.Errorf("Expected ErrAlreadyParty, got %v", err)
+	}
+
+	released, err := Release("pokemon1")
+	if err != nil || released.ID != 1 {
+		t.Fatalf("Expected to release #1, got %+v, %v", released, err)
+	}
+	if _, err := Inspect("1"); !errors.Is(err, ErrNotCaught) {
+		t.Errorf("Expected released pokemon to be gone, got %v", err)
+	}
+	if _, err := Release("pokemon2"); !errors.Is(err, ErrLastInParty) {
+		t.Errorf("Expected ErrLastInParty, got %v", err)
+	}
+}
+
+func TestDepositIntoOwnBoxStaysPut(t *testing.T) {
+	resetCollection(t)
+	fillCollection(t, PartySize+BoxSize)
+	boxed := "pokemon" + strconv.Itoa(PartySize+1)
+
+	for _, target := range []int{1, 0} {
+		deposited, box, err := Deposit(boxed, target)
+		if err != nil || box != 1 {
+			t.Errorf("Expected #%d to stay in its full box 1, got box %d, %v", deposited.ID, box, err)
+		}
+	}
+	if contents := BoxContents(1); len(contents) != BoxSize || contents[0].Pokemon.Name != boxed {
+		t.Errorf("Expected box 1 to be unchanged")
+	}
+}
+
+func TestLoadPlacesUnsortedPokemon(t *testing.T) {
+	resetCollection(t)
+	store := NewJSONFileStore(filepath.Join(t.TempDir(), "save.json"))
+	collection := Collection{NextID: 9, Party: []int{8, 404}, Boxes: [][]int{{}, {3}}}
+	for _, id := range []int{1, 3, 8} {
+		collection.Pokemon = append(collection.Pokemon, CaughtPokemon{ID: id})
+	}
+	err := store.Save(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadPokedex(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(party) != 2 || party[0] != 8 || party[1] != 1 || BoxOf(3) != 2 || nextID != 9 {
+		t.Errorf("Unexpected arrangement: party %v, boxes %v", party, boxes)
+	}
+}
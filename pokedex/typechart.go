@@ -0,0 +1,111 @@
+package pokedex
+
+import (
+	"context"
+	"sync"
+)
+
+// TypeChart is a type effectiveness matrix built lazily from the /type/
+// endpoint. It satisfies battle.TypeChart.
+type TypeChart struct {
+	client *Client
+	mu     sync.Mutex
+	loaded map[string]bool
+	// matrix[attacking][defending] holds every non-neutral multiplier seen so far.
+	matrix map[string]map[string]float64
+}
+
+func newTypeChart(client *Client) *TypeChart {
+	return &TypeChart{client: client, loaded: map[string]bool{}, matrix: map[string]map[string]float64{}}
+}
+
+// TypeChart returns the chart shared by everything using this client.
+func (client *Client) TypeChart() *TypeChart {
+	client.typeChartOnce.Do(func() {
+		client.typeChart = newTypeChart(client)
+	})
+	return client.typeChart
+}
+
+// Load fetches the damage relations of each defending type not loaded yet.
+func (chart *TypeChart) Load(ctx context.Context, types ...string) error {
+	for _, name := range types {
+		chart.mu.Lock()
+		loaded := chart.loaded[name]
+		chart.mu.Unlock()
+		if loaded {
+			continue
+		}
+		info, err := chart.client.fetchType(ctx, name)
+		if err != nil {
+			return err
+		}
+		chart.mu.Lock()
+		relations := info.DamageRelations
+		chart.set(relations.DoubleDamageFrom, name, 2)
+		chart.set(relations.HalfDamageFrom, name, 0.5)
+		chart.set(relations.NoDamageFrom, name, 0)
+		chart.loaded[name] = true
+		chart.mu.Unlock()
+	}
+	return nil
+}
+
+func (chart *TypeChart) set(attackers []PokemonEntity, defending string, multiplier float64) {
+	for _, attacker := range attackers {
+		if chart.matrix[attacker.Name] == nil {
+			chart.matrix[attacker.Name] = map[string]float64{}
+		}
+		chart.matrix[attacker.Name][defending] = multiplier
+	}
+}
+
+// Effectiveness treats defending types that were never loaded as neutral.
+func (chart *TypeChart) Effectiveness(attacking string, defending []string) float64 {
+	chart.mu.Lock()
+	defer chart.mu.Unlock()
+	multiplier := 1.0
+	for _, defender := range defending {
+		if value, exists := chart.matrix[attacking][defender]; exists {
+			multiplier *= value
+		}
+	}
+	return multiplier
+}
+
+// Weaknesses maps every attacking type that is not neutral against defending
+// to its multiplier. The defending types must have been loaded.
+func (chart *TypeChart) Weaknesses(defending []string) (multipliers map[string]float64) {
+	chart.mu.Lock()
+	attackers := []string{}
+	for attacking := range chart.matrix {
+		attackers = append(attackers, attacking)
+	}
+	chart.mu.Unlock()
+
+	multipliers = map[string]float64{}
+	for _, attacking := range attackers {
+		multiplier := chart.Effectiveness(attacking, defending)
+		if multiplier != 1 {
+			multipliers[attacking] = multiplier
+		}
+	}
+	return multipliers
+}
+
+// PokemonTypes returns the type names of the named Pokémon in slot order.
+func (client *Client) PokemonTypes(ctx context.Context, name string) (types []string, err error) {
+	pokemon, err := client.fetchPokemon(ctx, name)
+	if err != nil {
+		return []string{}, notFoundAs(ErrUnknownPokemon, name, err)
+	}
+	types = []string{}
+	for _, poketype := range pokemon.Types {
+		types = append(types, poketype.Type.Name)
+	}
+	return types, nil
+}
+
+func (client *Client) fetchType(ctx context.Context, name string) (info TypeInfo, err error) {
+	return fetchDecoded(ctx, client, client.decoded.types, client.endpoint("type/"+name))
+}
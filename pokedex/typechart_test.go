@@ -0,0 +1,42 @@
+package pokedex
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTypeChartWeaknesses(t *testing.T) {
+	client, hits := newTestClient(t)
+	types, err := client.PokemonTypes(context.Background(), "tentacool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart := client.TypeChart()
+	for i := 0; i < 2; i++ {
+		err = chart.Load(context.Background(), types...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits["/api/v2/type/water"] != 1 {
+		t.Errorf("Expected water to be fetched once, got %v", hits["/api/v2/type/water"])
+	}
+
+	weaknesses := chart.Weaknesses(types)
+	expected := map[string]float64{
+		"electric": 2, "ground": 2, "psychic": 2,
+		"fire": 0.5, "water": 0.5, "ice": 0.5, "steel": 0.5,
+		"fighting": 0.5, "poison": 0.5, "bug": 0.5, "fairy": 0.5,
+	}
+	if len(weaknesses) != len(expected) {
+		t.Errorf("Expected %v matchups, got %v", expected, weaknesses)
+	}
+	for attacking, multiplier := range expected {
+		if weaknesses[attacking] != multiplier {
+			t.Errorf("Expected %s to be %vx, got %vx", attacking, multiplier, weaknesses[attacking])
+		}
+	}
+	if _, exists := weaknesses["grass"]; exists {
+		t.Errorf("Expected grass to cancel out to neutral")
+	}
+}